@@ -2,13 +2,20 @@ package main
 
 import (
 	"database/sql"
+	"flag"
 	"log"
+	"os"
 	"salary-bot/config"
 	"salary-bot/internal/app/service"
 	"salary-bot/internal/delivery/telegram"
 	"salary-bot/internal/repository/sqlite"
 	"salary-bot/pkg/calendar"
+	"salary-bot/pkg/notification"
 	"salary-bot/pkg/workerpool"
+	"strings"
+	"time"
+	// база поясов встроена: /timezone работает и без tzdata в системе
+	_ "time/tzdata"
 
 	"gopkg.in/telebot.v3"
 
@@ -16,52 +23,132 @@ import (
 )
 
 func main() {
-	log.Println("Запуск Telegram Salary Bot...")
+	migrateDown := flag.Bool("migrate-down", false, "откатить последнюю миграцию БД и выйти")
+	configPath := flag.String("config", "", "файл конфигурации (по умолчанию CONFIG_FILE)")
+	printConfig := flag.Bool("print-config", false, "вывести итоговую конфигурацию без секретов и выйти")
+	flag.Parse()
 
-	cfg, err := config.LoadConfig()
+	cfg, err := config.Load(*configPath)
 	if err != nil {
 		log.Fatalf("Ошибка загрузки конфига: %v", err)
 	}
+	if *printConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			log.Fatalf("Ошибка вывода конфига: %v", err)
+		}
+		return
+	}
+
+	log.Println("Запуск Telegram Salary Bot...")
 
-	db, err := sql.Open("sqlite3", "salary-bot.db")
+	db, err := sql.Open("sqlite3", sqliteDSN(cfg.DBPath))
 	if err != nil {
 		log.Fatalf("Ошибка подключения к базе: %v", err)
 	}
 	defer db.Close()
 
+	if *migrateDown {
+		if err := sqlite.Rollback(db); err != nil {
+			log.Fatalf("Ошибка отката миграции: %v", err)
+		}
+		version, _ := sqlite.SchemaVersion(db)
+		log.Printf("Миграция откачена, текущая версия схемы: %d", version)
+		return
+	}
+
 	if err := sqlite.Migrate(db); err != nil {
 		log.Fatalf("Ошибка миграции: %v", err)
 	}
 
-	
-	pool := workerpool.NewWorkerPool(4, 32)
+	pool := workerpool.NewWorkerPool(cfg.Workers, cfg.QueueSize)
 	defer pool.Close()
 
+	audit := service.NewAuditService(sqlite.NewSqliteAuditRepo(db))
+
 	shiftRepo := sqlite.NewSqliteShiftRepo(db)
-	shiftService := &service.ShiftServiceImpl{Repo: shiftRepo}
+	employeeRepo := sqlite.NewSqliteEmployeeRepo(db)
+	shiftService := &service.ShiftServiceImpl{
+		Repo:      shiftRepo,
+		Payouts:   sqlite.NewSqlitePayoutRepo(db),
+		Audit:     audit,
+		Employees: employeeRepo,
+	}
 
 	pref := telebot.Settings{
 		Token:  cfg.TelegramToken,
-		Poller: &telebot.LongPoller{Timeout: 10},
+		Poller: &telebot.LongPoller{Timeout: cfg.PollerTimeout},
 	}
 	bot, err := telebot.NewBot(pref)
 	if err != nil {
 		log.Fatalf("Ошибка запуска бота: %v", err)
 	}
 
-	
-
 	calendarController := &calendar.CalendarController{Bot: bot}
-	
+
+	employeeService := service.NewEmployeeService(employeeRepo)
+	employeeService.Audit = audit
+	employeeService.Contexts = sqlite.NewSqliteManagerContextRepo(db)
+	employeeService.ManagerIDs = make(map[int64]bool, len(cfg.ManagerIDs))
+	for _, id := range cfg.ManagerIDs {
+		employeeService.ManagerIDs[id] = true
+	}
+	if err := employeeService.SyncManagerRoles(); err != nil {
+		log.Fatalf("Ошибка обновления ролей менеджеров: %v", err)
+	}
+
+	conversations := service.NewConversationService(sqlite.NewSqliteConversationRepo(db))
+	go conversations.RunCleanup(10 * time.Minute)
+
+	async := service.NewAsyncService(pool)
+
+	payslips, err := service.NewPayslipService(shiftService, employeeService, audit, cfg.PayslipTemplate)
+	if err != nil {
+		log.Fatalf("Ошибка шаблона расчётного листка: %v", err)
+	}
+
+	undo := service.NewUndoService(sqlite.NewSqliteUndoRepo(db), shiftService, cfg.UndoWindow)
+	go undo.RunCleanup(10 * time.Minute)
+
 	handler := &telegram.Handler{
-		Bot:       bot,
-		Shifts:    shiftService,
-		Async:     service.NewAsyncService(pool),
-		Employees: service.NewEmployeeService(sqlite.NewSqliteEmployeeRepo(db)),
-		Calendar:  calendarController,
+		Bot:           bot,
+		Shifts:        shiftService,
+		Async:         async,
+		Employees:     employeeService,
+		Calendar:      calendarController,
+		Conversations: conversations,
+		Rates:         &service.RateService{Repo: sqlite.NewSqliteRateCardRepo(db), Audit: audit},
+		Undo:          undo,
+		Audit:         audit,
+		Exports:       service.NewExportService(shiftService, async),
+		Payslips:      payslips,
+		Locale:        cfg.Locale,
+	}
+	if len(cfg.AllowedIDs) > 0 {
+		handler.Allowed = make(map[int64]bool, len(cfg.AllowedIDs)+len(cfg.ManagerIDs))
+		for _, id := range append(cfg.AllowedIDs, cfg.ManagerIDs...) {
+			handler.Allowed[id] = true
+		}
 	}
 	handler.Register()
 
+	// напоминания приходят в REMINDER_TIME по часовому поясу сотрудника
+	go handler.RunPaydayReminders(time.Minute, notification.Schedule{
+		Days: cfg.Paydays,
+		At:   cfg.ReminderTime,
+	})
+	go handler.RunEveningPrompts(time.Minute)
+	go handler.RunPayoutEscalations(time.Minute, cfg.PayoutConfirmDelay)
+
 	log.Println("Бот запущен!")
 	bot.Start()
 }
+
+// sqliteDSN добавляет к DB_PATH таймаут ожидания блокировки. DB_PATH может
+// уже содержать параметры ("file:bot.db?cache=shared").
+func sqliteDSN(path string) string {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + "_busy_timeout=5000"
+}
@@ -2,26 +2,283 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"salary-bot/pkg/i18n"
 
 	"github.com/joho/godotenv"
 )
 
+// Config — параметры бота. Значение берётся из окружения (в том числе .env),
+// иначе из файла конфигурации (CONFIG_FILE или флаг -config), иначе по
+// умолчанию.
 type Config struct {
 	TelegramToken string
+	// DBPath — путь к файлу базы SQLite.
+	DBPath string
+	// Workers и QueueSize — число воркеров фоновых задач (экспорт CSV/XLSX) и
+	// длина их очереди.
+	Workers   int
+	QueueSize int
+	// PollerTimeout — таймаут long polling запросов к Telegram.
+	PollerTimeout time.Duration
+	// ManagerIDs — Telegram ID пользователей, регистрируемых с ролью менеджера.
+	ManagerIDs []int64
+	// AllowedIDs — allowlist Telegram ID; пустой — бот доступен всем.
+	// Менеджеры из ManagerIDs допускаются всегда.
+	AllowedIDs []int64
+	// UndoWindow — сколько времени после действия доступна отмена через /undo.
+	UndoWindow time.Duration
+	// PayslipTemplate — путь к своему HTML-шаблону расчётного листка;
+	// пустой — встроенный шаблон.
+	PayslipTemplate string
+	// ReminderTime — время суток (смещение от полуночи) напоминаний о выплате.
+	ReminderTime time.Duration
+	// Paydays — дни месяца, в которые сотрудникам напоминают о выплате.
+	Paydays []int
+	// PayoutConfirmDelay — через сколько неподтверждённая второй стороной
+	// выплата эскалируется: повторный запрос и сообщение менеджерам.
+	PayoutConfirmDelay time.Duration
+	// Locale — язык сообщений для пользователей, чей язык в Telegram бот
+	// не поддерживает.
+	Locale i18n.Lang
 }
 
+// keys — параметры в порядке вывода -print-config; других ключей в файле
+// конфигурации быть не может.
+var keys = []string{
+	"TELEGRAM_TOKEN",
+	"DB_PATH",
+	"WORKERS",
+	"QUEUE_SIZE",
+	"POLLER_TIMEOUT",
+	"MANAGER_IDS",
+	"ALLOWED_IDS",
+	"UNDO_WINDOW",
+	"PAYSLIP_TEMPLATE",
+	"REMINDER_TIME",
+	"PAYDAYS",
+	"PAYOUT_CONFIRM_DELAY",
+	"LOCALE",
+}
 
+// LoadConfig читает конфигурацию из окружения и файла CONFIG_FILE, если он
+// задан.
 func LoadConfig() (*Config, error) {
+	return Load("")
+}
+
+// Load читает конфигурацию; path — файл конфигурации, пустой — CONFIG_FILE
+// из окружения или без файла.
+func Load(path string) (*Config, error) {
 	_ = godotenv.Load()
-	token := os.Getenv("TELEGRAM_TOKEN")
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	file := map[string]string{}
+	if path != "" {
+		var err error
+		file, err = readFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return parse(func(name string) string {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+		return file[name]
+	})
+}
+
+// parse собирает Config по значениям get и проверяет их.
+func parse(get func(name string) string) (*Config, error) {
+	token := get("TELEGRAM_TOKEN")
 	if token == "" {
 		return nil, ErrNoToken{}
 	}
-	return &Config{TelegramToken: token}, nil
+	dbPath := "salary-bot.db"
+	if v := get("DB_PATH"); v != "" {
+		dbPath = v
+	}
+	workers, err := parsePositive(get, "WORKERS", 4)
+	if err != nil {
+		return nil, err
+	}
+	queueSize, err := parsePositive(get, "QUEUE_SIZE", 32)
+	if err != nil {
+		return nil, err
+	}
+	pollerTimeout := 10 * time.Second
+	if v := get("POLLER_TIMEOUT"); v != "" {
+		pollerTimeout, err = time.ParseDuration(v)
+		if err != nil || pollerTimeout < time.Second {
+			return nil, ErrInvalidValue{Name: "POLLER_TIMEOUT", Value: v}
+		}
+	}
+	managers, err := parseIDList("MANAGER_IDS", get("MANAGER_IDS"))
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := parseIDList("ALLOWED_IDS", get("ALLOWED_IDS"))
+	if err != nil {
+		return nil, err
+	}
+	undoWindow := 10 * time.Minute
+	if v := get("UNDO_WINDOW"); v != "" {
+		undoWindow, err = time.ParseDuration(v)
+		if err != nil || undoWindow <= 0 {
+			return nil, ErrInvalidValue{Name: "UNDO_WINDOW", Value: v}
+		}
+	}
+	reminderTime := 10 * time.Hour
+	if v := get("REMINDER_TIME"); v != "" {
+		reminderTime, err = parseClock(v)
+		if err != nil {
+			return nil, ErrInvalidValue{Name: "REMINDER_TIME", Value: v}
+		}
+	}
+	confirmDelay := 24 * time.Hour
+	if v := get("PAYOUT_CONFIRM_DELAY"); v != "" {
+		confirmDelay, err = time.ParseDuration(v)
+		if err != nil || confirmDelay <= 0 {
+			return nil, ErrInvalidValue{Name: "PAYOUT_CONFIRM_DELAY", Value: v}
+		}
+	}
+	paydays := []int{10, 25}
+	if v := get("PAYDAYS"); v != "" {
+		paydays, err = parseDays("PAYDAYS", v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	locale := i18n.Default
+	if v := get("LOCALE"); v != "" {
+		locale, err = i18n.Parse(v)
+		if err != nil {
+			return nil, ErrInvalidValue{Name: "LOCALE", Value: v}
+		}
+	}
+	return &Config{
+		TelegramToken:      token,
+		DBPath:             dbPath,
+		Workers:            workers,
+		QueueSize:          queueSize,
+		PollerTimeout:      pollerTimeout,
+		ManagerIDs:         managers,
+		AllowedIDs:         allowed,
+		UndoWindow:         undoWindow,
+		PayslipTemplate:    get("PAYSLIP_TEMPLATE"),
+		ReminderTime:       reminderTime,
+		Paydays:            paydays,
+		PayoutConfirmDelay: confirmDelay,
+		Locale:             locale,
+	}, nil
+}
+
+// parsePositive разбирает целое больше нуля; пустое значение — def.
+func parsePositive(get func(name string) string, name string, def int) (int, error) {
+	v := get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || n <= 0 {
+		return 0, ErrInvalidValue{Name: name, Value: v}
+	}
+	return n, nil
+}
+
+// parseIDList разбирает список Telegram ID через запятую.
+func parseIDList(name, value string) ([]int64, error) {
+	var ids []int64
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, ErrInvalidValue{Name: name, Value: part}
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseClock разбирает время суток ЧЧ:ММ.
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// parseDays разбирает список дней месяца через запятую.
+func parseDays(name, value string) ([]int, error) {
+	var days []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		d, err := strconv.Atoi(part)
+		if err != nil || d < 1 || d > 31 {
+			return nil, ErrInvalidValue{Name: name, Value: part}
+		}
+		days = append(days, d)
+	}
+	if len(days) == 0 {
+		return nil, ErrInvalidValue{Name: name, Value: value}
+	}
+	return days, nil
 }
 
 type ErrNoToken struct{}
 
 func (e ErrNoToken) Error() string {
-	return "TELEGRAM_TOKEN не задан в окружении"
+	return "TELEGRAM_TOKEN не задан ни в окружении, ни в файле конфигурации"
+}
+
+type ErrInvalidValue struct {
+	Name  string
+	Value string
+}
+
+func (e ErrInvalidValue) Error() string {
+	return "некорректное значение " + e.Name + ": " + e.Value
+}
+
+// ErrConfigFile — файл конфигурации не прочитан или строка Line в нём не
+// разобрана.
+type ErrConfigFile struct {
+	Path string
+	Line int
+	Err  error
+}
+
+func (e ErrConfigFile) Error() string {
+	where := e.Path
+	if e.Line > 0 {
+		where += ":" + strconv.Itoa(e.Line)
+	}
+	return "файл конфигурации " + where + ": " + e.Err.Error()
+}
+
+func (e ErrConfigFile) Unwrap() error {
+	return e.Err
+}
+
+// ErrUnknownKey — в файле конфигурации параметр, которого бот не знает.
+type ErrUnknownKey struct {
+	Path string
+	Line int
+	Key  string
+}
+
+func (e ErrUnknownKey) Error() string {
+	return "неизвестный параметр " + e.Key + " в " + e.Path + ":" + strconv.Itoa(e.Line)
 }
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv убирает параметры бота из окружения на время теста.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	for _, k := range keys {
+		t.Setenv(k, "")
+	}
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+	}{
+		{"yaml", "db_path: ./bot.db\nworkers: 2\n", map[string]string{"DB_PATH": "./bot.db", "WORKERS": "2"}},
+		{"toml", "[bot]\ndb-path = \"bot.db\"\npaydays = [5, 20]\n", map[string]string{"DB_PATH": "bot.db", "PAYDAYS": "5, 20"}},
+		{"comments", "# токен\n---\n\nlocale: en # язык\n", map[string]string{"LOCALE": "en"}},
+		{"quoted", "reminder_time: \"10:00\"\nmanager_ids: ''\n", map[string]string{"REMINDER_TIME": "10:00", "MANAGER_IDS": ""}},
+		{"case", "Undo_Window: 5m\n", map[string]string{"UNDO_WINDOW": "5m"}},
+		{"crlf", "workers: 3\r\n", map[string]string{"WORKERS": "3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readFile(writeConfig(t, tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readFile = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadFileErrors(t *testing.T) {
+	path := writeConfig(t, "workers: 2\n\ndb_pth: bot.db\n")
+	_, err := readFile(path)
+	var unknown ErrUnknownKey
+	if !errors.As(err, &unknown) || unknown.Key != "db_pth" || unknown.Line != 3 || unknown.Path != path {
+		t.Errorf("unknown key: err = %#v", err)
+	}
+
+	for _, content := range []string{"workers\n", "\"db path\": x\n", "locale: \"en\n", ": x\n"} {
+		_, err := readFile(writeConfig(t, "workers: 2\n"+content))
+		var cfgErr ErrConfigFile
+		if !errors.As(err, &cfgErr) || cfgErr.Line != 2 || !errors.Is(err, errSyntax) {
+			t.Errorf("%q: err = %#v, want ErrConfigFile at line 2", content, err)
+		}
+	}
+
+	_, err = readFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	var cfgErr ErrConfigFile
+	if !errors.As(err, &cfgErr) || cfgErr.Line != 0 || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing file: err = %#v", err)
+	}
+	if strings.Contains(cfgErr.Error(), ":0:") {
+		t.Errorf("missing file message has line 0: %s", cfgErr.Error())
+	}
+}
+
+func TestParse(t *testing.T) {
+	get := func(values map[string]string) func(string) string {
+		return func(name string) string { return values[name] }
+	}
+	cfg, err := parse(get(map[string]string{"TELEGRAM_TOKEN": "1:x"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DBPath != "salary-bot.db" || cfg.Workers != 4 || cfg.QueueSize != 32 ||
+		cfg.ReminderTime != 10*time.Hour || !reflect.DeepEqual(cfg.Paydays, []int{10, 25}) {
+		t.Errorf("defaults = %+v", cfg)
+	}
+
+	if _, err := parse(get(nil)); !errors.As(err, &ErrNoToken{}) {
+		t.Errorf("no token: err = %v", err)
+	}
+
+	invalid := []struct{ name, value, bad string }{
+		{"WORKERS", "0", "0"},
+		{"QUEUE_SIZE", "много", "много"},
+		{"POLLER_TIMEOUT", "500ms", "500ms"},
+		{"MANAGER_IDS", "1, x", "x"},
+		{"ALLOWED_IDS", "1.5", "1.5"},
+		{"UNDO_WINDOW", "-1m", "-1m"},
+		{"REMINDER_TIME", "25:00", "25:00"},
+		{"PAYDAYS", "10, 32", "32"},
+		{"PAYDAYS", ",", ","},
+		{"PAYOUT_CONFIRM_DELAY", "day", "day"},
+		{"LOCALE", "de", "de"},
+	}
+	for _, tt := range invalid {
+		_, err := parse(get(map[string]string{"TELEGRAM_TOKEN": "1:x", tt.name: tt.value}))
+		want := ErrInvalidValue{Name: tt.name, Value: tt.bad}
+		if err != want {
+			t.Errorf("%s=%q: err = %v, want %v", tt.name, tt.value, err, want)
+		}
+	}
+}
+
+func TestLoadEnvOverridesFile(t *testing.T) {
+	clearEnv(t)
+	path := writeConfig(t, "telegram_token: 1:file\ndb_path: file.db\nworkers: 2\n")
+	t.Setenv("DB_PATH", "env.db")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DBPath != "env.db" || cfg.Workers != 2 || cfg.TelegramToken != "1:file" {
+		t.Errorf("cfg = %+v, want DB_PATH from env, the rest from file", cfg)
+	}
+
+	// без пути Load берёт файл из CONFIG_FILE
+	t.Setenv("CONFIG_FILE", path)
+	if cfg, err = Load(""); err != nil || cfg.Workers != 2 {
+		t.Errorf("CONFIG_FILE: cfg = %+v, err = %v", cfg, err)
+	}
+}
+
+func TestPrintRedactsToken(t *testing.T) {
+	tests := []struct{ token, want string }{
+		{"123456:AAE-secret", "TELEGRAM_TOKEN=123456:***\n"},
+		{"secret", "TELEGRAM_TOKEN=***\n"},
+		{"", "TELEGRAM_TOKEN=\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		cfg := &Config{TelegramToken: tt.token, Paydays: []int{10, 25}, ReminderTime: 9*time.Hour + 30*time.Minute}
+		if err := cfg.Print(&buf); err != nil {
+			t.Fatal(err)
+		}
+		out := buf.String()
+		if !strings.HasPrefix(out, tt.want) || strings.Contains(out, "secret") {
+			t.Errorf("token %q: output\n%s", tt.token, out)
+		}
+		if !strings.Contains(out, "PAYDAYS=10,25\n") || !strings.Contains(out, "REMINDER_TIME=09:30\n") {
+			t.Errorf("output\n%s", out)
+		}
+		if n := strings.Count(out, "\n"); n != len(keys) {
+			t.Errorf("%d lines, want %d", n, len(keys))
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+var errSyntax = errors.New("ожидается «ключ: значение» или «ключ = значение»")
+
+// readFile читает файл конфигурации в простом формате, общем для YAML и
+// TOML: по строке «ключ: значение» или «ключ = значение», комментарии
+// после #, заголовки секций [bot] пропускаются. Ключи — имена переменных
+// окружения без учёта регистра, db-path и db_path равнозначны. Списки
+// пишутся через запятую, можно в квадратных скобках: paydays = [10, 25].
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ErrConfigFile{Path: path, Err: err}
+	}
+	known := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		known[k] = true
+	}
+	values := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line == "---" ||
+			strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			continue
+		}
+		key, value, ok := cutKeyValue(line)
+		if !ok {
+			return nil, ErrConfigFile{Path: path, Line: i + 1, Err: errSyntax}
+		}
+		name := strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+		if !known[name] {
+			return nil, ErrUnknownKey{Path: path, Line: i + 1, Key: key}
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// cutKeyValue делит строку по первому «:» или «=» и очищает значение от
+// кавычек, скобок списка и комментария.
+func cutKeyValue(line string) (key, value string, ok bool) {
+	i := strings.IndexAny(line, ":=")
+	if i <= 0 {
+		return "", "", false
+	}
+	key = strings.TrimSpace(line[:i])
+	if strings.ContainsAny(key, " \t\"'") {
+		return "", "", false
+	}
+	value = strings.TrimSpace(line[i+1:])
+	if n := len(value); n >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return key, value[1 : end+1], true
+		}
+		return "", "", false
+	}
+	if j := strings.Index(value, " #"); j >= 0 {
+		value = strings.TrimSpace(value[:j])
+	}
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		value = strings.TrimSpace(value[1 : len(value)-1])
+	}
+	return key, value, true
+}
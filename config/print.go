@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Print выводит итоговую конфигурацию в формате .env. Токен скрыт: виден
+// только ID бота до двоеточия.
+func (c *Config) Print(w io.Writer) error {
+	values := map[string]string{
+		"TELEGRAM_TOKEN":       redact(c.TelegramToken),
+		"DB_PATH":              c.DBPath,
+		"WORKERS":              strconv.Itoa(c.Workers),
+		"QUEUE_SIZE":           strconv.Itoa(c.QueueSize),
+		"POLLER_TIMEOUT":       c.PollerTimeout.String(),
+		"MANAGER_IDS":          joinIDs(c.ManagerIDs),
+		"ALLOWED_IDS":          joinIDs(c.AllowedIDs),
+		"UNDO_WINDOW":          c.UndoWindow.String(),
+		"PAYSLIP_TEMPLATE":     c.PayslipTemplate,
+		"REMINDER_TIME":        formatClock(c.ReminderTime),
+		"PAYOUT_CONFIRM_DELAY": c.PayoutConfirmDelay.String(),
+		"LOCALE":               string(c.Locale),
+	}
+	days := make([]string, len(c.Paydays))
+	for i, d := range c.Paydays {
+		days[i] = strconv.Itoa(d)
+	}
+	values["PAYDAYS"] = strings.Join(days, ",")
+	for _, k := range keys {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", k, values[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// redact скрывает секретную часть токена вида 123456:ABC...
+func redact(token string) string {
+	if token == "" {
+		return ""
+	}
+	if id, _, ok := strings.Cut(token, ":"); ok {
+		return id + ":***"
+	}
+	return "***"
+}
+
+func joinIDs(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// formatClock — время суток ЧЧ:ММ, обратное parseClock.
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"salary-bot/internal/domain"
+)
+
+// AuditService пишет журнал изменений. Запись в журнал выполняется после
+// успешного изменения; ошибка записи журнала не отменяет само изменение,
+// но попадает в лог.
+type AuditService struct {
+	Repo domain.AuditRepo
+}
+
+func NewAuditService(repo domain.AuditRepo) *AuditService {
+	return &AuditService{Repo: repo}
+}
+
+// Record добавляет запись; before и after сериализуются в JSON, nil — нет снимка.
+func (s *AuditService) Record(actorID int64, employeeID int, action, entity string, entityID int, before, after any) {
+	if s == nil {
+		return
+	}
+	e := domain.AuditEntry{
+		ActorID:    actorID,
+		EmployeeID: employeeID,
+		Action:     action,
+		Entity:     entity,
+		EntityID:   entityID,
+		Before:     snapshot(before),
+		After:      snapshot(after),
+		CreatedAt:  time.Now(),
+	}
+	if err := s.Repo.AppendAudit(e); err != nil {
+		log.Printf("[audit] %s %s #%d by %d: %v", action, entity, entityID, actorID, err)
+	}
+}
+
+func (s *AuditService) History(userID int64, limit int) ([]domain.AuditEntry, error) {
+	return s.Repo.GetAuditEntries(userID, limit)
+}
+
+// EntityHistory — все изменения сущностей entity сотрудника по порядку.
+func (s *AuditService) EntityHistory(employeeID int, entity string) ([]domain.AuditEntry, error) {
+	return s.Repo.GetEntityAudit(employeeID, entity)
+}
+
+func snapshot(v any) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("[audit] snapshot %T: %v", v, err)
+		return ""
+	}
+	return string(b)
+}
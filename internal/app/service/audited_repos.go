@@ -0,0 +1,252 @@
+package service
+
+import (
+	"time"
+
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/money"
+)
+
+// Обёртки над репозиториями, которые пишут в журнал аудита каждое
+// изменение от имени actor. Чтение проходит к исходному репозиторию.
+
+type auditedShiftRepo struct {
+	domain.ShiftRepo
+	// payouts — для снимков выплат, которые подтверждает ApproveShifts.
+	payouts domain.PayoutRepo
+	audit   *AuditService
+	actor   int64
+}
+
+func (r auditedShiftRepo) AddShift(shift domain.DomainShift) (int, error) {
+	id, err := r.ShiftRepo.AddShift(shift)
+	if err != nil {
+		return id, err
+	}
+	shift.ID = id
+	r.audit.Record(r.actor, shift.EmployeeID, domain.AuditCreate, domain.EntityShift, id, nil, shift)
+	return id, nil
+}
+
+func (r auditedShiftRepo) AddShifts(shifts []domain.DomainShift, payouts []domain.BatchPayout) ([]int, []int, error) {
+	ids, payoutIDs, err := r.ShiftRepo.AddShifts(shifts, payouts)
+	if err != nil {
+		return ids, payoutIDs, err
+	}
+	for i, id := range ids {
+		shift := shifts[i]
+		shift.ID = id
+		r.audit.Record(r.actor, shift.EmployeeID, domain.AuditCreate, domain.EntityShift, id, nil, shift)
+	}
+	for i, id := range payoutIDs {
+		p := payouts[i].Payout
+		p.ID = id
+		allocations := payouts[i].Allocations(shifts, ids)
+		for j := range allocations {
+			allocations[j].PayoutID = id
+		}
+		r.audit.Record(r.actor, p.EmployeeID, domain.AuditCreate, domain.EntityPayout, id, nil, payoutSnapshot{p, allocations})
+	}
+	return ids, payoutIDs, nil
+}
+
+func (r auditedShiftRepo) UpdateShiftAmount(id int, amount money.Amount) error {
+	return r.update(id, func() error { return r.ShiftRepo.UpdateShiftAmount(id, amount) })
+}
+
+func (r auditedShiftRepo) UpdateShiftDate(id int, date time.Time) error {
+	return r.update(id, func() error { return r.ShiftRepo.UpdateShiftDate(id, date) })
+}
+
+func (r auditedShiftRepo) UpdateShiftStatus(id int, status string) error {
+	return r.update(id, func() error { return r.ShiftRepo.UpdateShiftStatus(id, status) })
+}
+
+func (r auditedShiftRepo) ApproveShifts(ids, payoutIDs []int) error {
+	shifts := make([]domain.DomainShift, 0, len(ids))
+	for _, id := range ids {
+		sh, err := r.ShiftRepo.GetShiftByID(id)
+		if err != nil {
+			return err
+		}
+		shifts = append(shifts, sh)
+	}
+	payouts := make([]payoutSnapshot, 0, len(payoutIDs))
+	for _, id := range payoutIDs {
+		p, err := r.payouts.GetPayout(id)
+		if err != nil {
+			return err
+		}
+		allocations, err := r.payouts.GetAllocations(id)
+		if err != nil {
+			return err
+		}
+		payouts = append(payouts, payoutSnapshot{p, allocations})
+	}
+	if err := r.ShiftRepo.ApproveShifts(ids, payoutIDs); err != nil {
+		return err
+	}
+	for _, before := range shifts {
+		after := before
+		after.Status = domain.ShiftApproved
+		r.audit.Record(r.actor, before.EmployeeID, domain.AuditUpdate, domain.EntityShift, before.ID, before, after)
+	}
+	for _, before := range payouts {
+		after := before
+		after.Payout.Status = domain.PayoutConfirmed
+		r.audit.Record(r.actor, before.Payout.EmployeeID, domain.AuditUpdate, domain.EntityPayout, before.Payout.ID, before, after)
+	}
+	return nil
+}
+
+func (r auditedShiftRepo) update(id int, fn func() error) error {
+	before, err := r.ShiftRepo.GetShiftByID(id)
+	if err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	after, err := r.ShiftRepo.GetShiftByID(id)
+	if err != nil {
+		return err
+	}
+	r.audit.Record(r.actor, before.EmployeeID, domain.AuditUpdate, domain.EntityShift, id, before, after)
+	return nil
+}
+
+func (r auditedShiftRepo) DeleteShift(id int) error {
+	before, err := r.ShiftRepo.GetShiftByID(id)
+	if err != nil {
+		return err
+	}
+	if err := r.ShiftRepo.DeleteShift(id); err != nil {
+		return err
+	}
+	r.audit.Record(r.actor, before.EmployeeID, domain.AuditDelete, domain.EntityShift, id, before, nil)
+	return nil
+}
+
+func (r auditedShiftRepo) DeleteByEmployee(employeeID int) error {
+	before, err := r.ShiftRepo.GetShifts(employeeID, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Now().AddDate(10, 0, 0))
+	if err != nil {
+		return err
+	}
+	if err := r.ShiftRepo.DeleteByEmployee(employeeID); err != nil {
+		return err
+	}
+	r.audit.Record(r.actor, employeeID, domain.AuditDeleteAll, domain.EntityShift, 0, before, nil)
+	return nil
+}
+
+type auditedPayoutRepo struct {
+	domain.PayoutRepo
+	audit *AuditService
+	actor int64
+}
+
+// payoutSnapshot — выплата вместе с распределением по сменам.
+type payoutSnapshot struct {
+	Payout      domain.Payout
+	Allocations []domain.PayoutAllocation
+}
+
+func (r auditedPayoutRepo) CreatePayout(p domain.Payout, allocations []domain.PayoutAllocation) (int, error) {
+	id, err := r.PayoutRepo.CreatePayout(p, allocations)
+	if err != nil {
+		return id, err
+	}
+	p.ID = id
+	for i := range allocations {
+		allocations[i].PayoutID = id
+	}
+	r.audit.Record(r.actor, p.EmployeeID, domain.AuditCreate, domain.EntityPayout, id, nil, payoutSnapshot{p, allocations})
+	return id, nil
+}
+
+func (r auditedPayoutRepo) DeletePayout(id int) error {
+	p, err := r.PayoutRepo.GetPayout(id)
+	if err != nil {
+		return err
+	}
+	allocations, err := r.PayoutRepo.GetAllocations(id)
+	if err != nil {
+		return err
+	}
+	if err := r.PayoutRepo.DeletePayout(id); err != nil {
+		return err
+	}
+	r.audit.Record(r.actor, p.EmployeeID, domain.AuditDelete, domain.EntityPayout, id, payoutSnapshot{p, allocations}, nil)
+	return nil
+}
+
+func (r auditedPayoutRepo) UpdatePayoutStatus(id int, status string) error {
+	before, err := r.PayoutRepo.GetPayout(id)
+	if err != nil {
+		return err
+	}
+	allocations, err := r.PayoutRepo.GetAllocations(id)
+	if err != nil {
+		return err
+	}
+	if err := r.PayoutRepo.UpdatePayoutStatus(id, status); err != nil {
+		return err
+	}
+	after := payoutSnapshot{Payout: before, Allocations: allocations}
+	after.Payout.Status = status
+	if status == domain.PayoutDisputed {
+		after.Allocations = nil
+	}
+	r.audit.Record(r.actor, before.EmployeeID, domain.AuditUpdate, domain.EntityPayout, id,
+		payoutSnapshot{before, allocations}, after)
+	return nil
+}
+
+func (r auditedPayoutRepo) DeleteByEmployee(employeeID int) error {
+	before, err := r.PayoutRepo.GetPayouts(employeeID, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Now().AddDate(10, 0, 0))
+	if err != nil {
+		return err
+	}
+	if err := r.PayoutRepo.DeleteByEmployee(employeeID); err != nil {
+		return err
+	}
+	r.audit.Record(r.actor, employeeID, domain.AuditDeleteAll, domain.EntityPayout, 0, before, nil)
+	return nil
+}
+
+type auditedEmployeeRepo struct {
+	domain.EmployeeRepo
+	audit *AuditService
+	actor int64
+}
+
+func (r auditedEmployeeRepo) CreateOrUpdateEmployee(e domain.Employee) error {
+	var before any
+	action := domain.AuditCreate
+	if old, err := r.EmployeeRepo.GetEmployeeByID(e.ID); err == nil {
+		if old == e {
+			return nil
+		}
+		before = old
+		action = domain.AuditUpdate
+	}
+	if err := r.EmployeeRepo.CreateOrUpdateEmployee(e); err != nil {
+		return err
+	}
+	r.audit.Record(r.actor, e.ID, action, domain.EntityEmployee, e.ID, before, e)
+	return nil
+}
+
+type auditedRateCardRepo struct {
+	domain.RateCardRepo
+	audit *AuditService
+	actor int64
+}
+
+func (r auditedRateCardRepo) AddRateCard(rc domain.RateCard) error {
+	if err := r.RateCardRepo.AddRateCard(rc); err != nil {
+		return err
+	}
+	r.audit.Record(r.actor, rc.EmployeeID, domain.AuditCreate, domain.EntityRateCard, 0, nil, rc)
+	return nil
+}
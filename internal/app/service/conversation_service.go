@@ -0,0 +1,69 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"salary-bot/internal/domain"
+)
+
+// DefaultConversationTTL — сколько ждём ввода от пользователя, прежде чем
+// забыть незавершённый сценарий.
+const DefaultConversationTTL = 30 * time.Minute
+
+// ConversationService — конечный автомат диалогов, хранящийся в БД.
+// Состояние переживает перезапуск бота, а завершение шага через Complete
+// атомарно: из двух одновременных сообщений шаг выполнит только одно.
+type ConversationService struct {
+	Repo domain.ConversationRepo
+	TTL  time.Duration
+}
+
+func NewConversationService(repo domain.ConversationRepo) *ConversationService {
+	return &ConversationService{Repo: repo, TTL: DefaultConversationTTL}
+}
+
+func (s *ConversationService) Get(chatID int64) (domain.Conversation, error) {
+	return s.Repo.GetConversation(chatID, time.Now())
+}
+
+// Set переводит чат в состояние state, заменяя любое предыдущее.
+func (s *ConversationService) Set(chatID int64, state string, data map[string]string) error {
+	if data == nil {
+		data = map[string]string{}
+	}
+	return s.Repo.SaveConversation(domain.Conversation{
+		ChatID:    chatID,
+		State:     state,
+		Data:      data,
+		ExpiresAt: time.Now().Add(s.TTL),
+	})
+}
+
+// Complete завершает шаг state. false означает, что чат уже не в этом
+// состоянии (шаг отменён, истёк или его выполнил параллельный апдейт).
+func (s *ConversationService) Complete(chatID int64, state string) (bool, error) {
+	return s.Repo.DeleteConversation(chatID, state)
+}
+
+// Cancel сбрасывает любой незавершённый сценарий чата.
+func (s *ConversationService) Cancel(chatID int64) error {
+	_, err := s.Repo.DeleteConversation(chatID, domain.StateIdle)
+	return err
+}
+
+// RunCleanup периодически удаляет истёкшие состояния. Блокирует вызывающего.
+func (s *ConversationService) RunCleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		n, err := s.Repo.DeleteExpiredConversations(time.Now())
+		if err != nil {
+			log.Printf("[state] cleanup: %v", err)
+			continue
+		}
+		if n > 0 {
+			log.Printf("[state] cleanup removed %d expired conversations", n)
+		}
+	}
+}
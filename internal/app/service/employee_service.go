@@ -1,9 +1,34 @@
 package service
 
-import "salary-bot/internal/domain"
+import (
+	"errors"
+	"time"
+
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/money"
+)
+
+var ErrNotManager = errors.New("действие доступно только менеджеру")
 
 type EmployeeService struct {
 	Repo domain.EmployeeRepo
+	// Contexts — выбранный менеджером сотрудник; nil — режим менеджера выключен.
+	Contexts domain.ManagerContextRepo
+	// ManagerIDs — Telegram ID менеджеров. Роль сотрудника всегда следует
+	// этому списку: добавленный в MANAGER_IDS позже становится менеджером
+	// при следующем обращении или перезапуске.
+	ManagerIDs map[int64]bool
+	Audit      *AuditService
+}
+
+// As возвращает сервис, изменения через который пишутся в журнал аудита от имени actor.
+func (s *EmployeeService) As(actor int64) *EmployeeService {
+	if s.Audit == nil {
+		return s
+	}
+	cp := *s
+	cp.Repo = auditedEmployeeRepo{EmployeeRepo: s.Repo, audit: s.Audit, actor: actor}
+	return &cp
 }
 
 func (s *EmployeeService) CreateOrUpdateEmployee(e domain.Employee) error {
@@ -14,6 +39,176 @@ func NewEmployeeService(repo domain.EmployeeRepo) *EmployeeService {
 	return &EmployeeService{Repo: repo}
 }
 
+// Register создаёт сотрудника при первом обращении или обновляет имя,
+// username, чат и роль по ManagerIDs у существующего.
+func (s *EmployeeService) Register(telegramID int64, chatID int64, name, username string) (domain.Employee, error) {
+	e, err := s.Repo.GetEmployeeByID(int(telegramID))
+	switch {
+	case errors.Is(err, domain.ErrEmployeeNotFound):
+		e = domain.Employee{ID: int(telegramID), PayType: domain.PayPerShift}
+	case err != nil:
+		return domain.Employee{}, err
+	}
+	e.Role = s.roleOf(telegramID)
+	e.Name = name
+	e.Username = username
+	e.ChatID = chatID
+	if err := s.Repo.CreateOrUpdateEmployee(e); err != nil {
+		return domain.Employee{}, err
+	}
+	return e, nil
+}
+
+// SyncManagerRoles приводит роли уже зарегистрированных сотрудников
+// к ManagerIDs. Вызывается при запуске, чтобы изменения MANAGER_IDS
+// действовали и для тех, кто ещё не писал боту.
+func (s *EmployeeService) SyncManagerRoles() error {
+	employees, err := s.Repo.GetAllEmployees()
+	if err != nil {
+		return err
+	}
+	for _, e := range employees {
+		role := s.roleOf(int64(e.ID))
+		if e.Role == role {
+			continue
+		}
+		e.Role = role
+		if err := s.Repo.CreateOrUpdateEmployee(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *EmployeeService) roleOf(telegramID int64) string {
+	if s.ManagerIDs[telegramID] {
+		return domain.RoleManager
+	}
+	return domain.RoleEmployee
+}
+
+// SetPayType переключает сотрудника между оплатой за смену и почасовой.
+func (s *EmployeeService) SetPayType(id int, payType string, hourlyRate money.Amount) (domain.Employee, error) {
+	e, err := s.Repo.GetEmployeeByID(id)
+	if err != nil {
+		return e, err
+	}
+	e.PayType = payType
+	e.HourlyRate = hourlyRate
+	return e, s.Repo.CreateOrUpdateEmployee(e)
+}
+
+// SetPromptTime включает вечерний вопрос о смене в clock (ЧЧ:ММ) или
+// выключает его пустой строкой.
+func (s *EmployeeService) SetPromptTime(id int, clock string) (domain.Employee, error) {
+	e, err := s.Repo.GetEmployeeByID(id)
+	if err != nil {
+		return e, err
+	}
+	e.PromptTime = clock
+	return e, s.Repo.CreateOrUpdateEmployee(e)
+}
+
+// SetCurrency меняет валюту оплаты сотрудника. Уже записанные смены и
+// выплаты остаются в своей валюте.
+func (s *EmployeeService) SetCurrency(id int, currency money.Currency) (domain.Employee, error) {
+	e, err := s.Repo.GetEmployeeByID(id)
+	if err != nil {
+		return e, err
+	}
+	e.Currency = currency
+	return e, s.Repo.CreateOrUpdateEmployee(e)
+}
+
+// SetLanguage запоминает язык сообщений сотрудника; пустой — язык из
+// настроек Telegram.
+func (s *EmployeeService) SetLanguage(id int, lang string) (domain.Employee, error) {
+	e, err := s.Repo.GetEmployeeByID(id)
+	if err != nil {
+		return e, err
+	}
+	e.Language = lang
+	return e, s.Repo.CreateOrUpdateEmployee(e)
+}
+
+// SetTimeZone запоминает часовой пояс сотрудника (имя IANA).
+func (s *EmployeeService) SetTimeZone(id int, zone string) (domain.Employee, error) {
+	if _, err := time.LoadLocation(zone); err != nil || zone == "" {
+		return domain.Employee{}, domain.ErrInvalidTimeZone
+	}
+	e, err := s.Repo.GetEmployeeByID(id)
+	if err != nil {
+		return e, err
+	}
+	e.TimeZone = zone
+	return e, s.Repo.CreateOrUpdateEmployee(e)
+}
+
+func (s *EmployeeService) ClaimPrompt(id int, date time.Time) (bool, error) {
+	return s.Repo.ClaimPrompt(id, date)
+}
+
+func (s *EmployeeService) ClaimReminder(id int, prev, now time.Time) (bool, error) {
+	return s.Repo.ClaimReminder(id, prev, now)
+}
+
+func (s *EmployeeService) ReleasePrompt(id int, date time.Time) error {
+	return s.Repo.ReleasePrompt(id, date)
+}
+
+func (s *EmployeeService) ReleaseReminder(id int, now, prev time.Time) error {
+	return s.Repo.ReleaseReminder(id, now, prev)
+}
+
+// Target возвращает сотрудника, с данными которого работает пользователь:
+// для менеджера — выбранного через ActFor, для остальных — его самого.
+// Незарегистрированный пользователь получает Employee только с ID.
+func (s *EmployeeService) Target(userID int64) (domain.Employee, error) {
+	me, err := s.Repo.GetEmployeeByID(int(userID))
+	if errors.Is(err, domain.ErrEmployeeNotFound) {
+		return domain.Employee{ID: int(userID)}, nil
+	}
+	if err != nil || !me.IsManager() || s.Contexts == nil {
+		return me, err
+	}
+	employeeID, ok, err := s.Contexts.GetManagerContext(userID)
+	if err != nil || !ok {
+		return me, err
+	}
+	target, err := s.Repo.GetEmployeeByID(employeeID)
+	if errors.Is(err, domain.ErrEmployeeNotFound) {
+		return me, s.Contexts.ClearManagerContext(userID)
+	}
+	return target, err
+}
+
+// ActFor переключает менеджера на работу с данными сотрудника employeeID.
+func (s *EmployeeService) ActFor(managerID int64, employeeID int) (domain.Employee, error) {
+	me, err := s.Repo.GetEmployeeByID(int(managerID))
+	if err != nil {
+		return domain.Employee{}, err
+	}
+	if !me.IsManager() || s.Contexts == nil {
+		return domain.Employee{}, ErrNotManager
+	}
+	target, err := s.Repo.GetEmployeeByID(employeeID)
+	if err != nil {
+		return target, err
+	}
+	if target.ID == me.ID {
+		return me, s.Contexts.ClearManagerContext(managerID)
+	}
+	return target, s.Contexts.SetManagerContext(managerID, employeeID)
+}
+
+// StopActing возвращает менеджера к его собственным данным.
+func (s *EmployeeService) StopActing(managerID int64) error {
+	if s.Contexts == nil {
+		return nil
+	}
+	return s.Contexts.ClearManagerContext(managerID)
+}
+
 func (s *EmployeeService) GetAllEmployees() ([]domain.Employee, error) {
 	return s.Repo.GetAllEmployees()
 }
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/i18n"
+	"salary-bot/pkg/money"
+	"salary-bot/pkg/xlsx"
+)
+
+// Report — данные для выгрузки за период: смены с номерами покрывающих
+// их выплат и выплаты, проведённые в этот период.
+type Report struct {
+	EmployeeID int
+	From, To   time.Time
+	Shifts     []ReportShift
+	Payouts    []domain.Payout
+}
+
+type ReportShift struct {
+	domain.DomainShift
+	PayoutIDs []int
+}
+
+type ExportService struct {
+	Shifts *ShiftServiceImpl
+	Async  *AsyncService
+}
+
+func NewExportService(shifts *ShiftServiceImpl, async *AsyncService) *ExportService {
+	return &ExportService{Shifts: shifts, Async: async}
+}
+
+func (s *ExportService) BuildReport(employeeID int, from, to time.Time) (Report, error) {
+	r := Report{EmployeeID: employeeID, From: from, To: to}
+	shifts, err := s.Shifts.GetShifts(employeeID, from, to)
+	if err != nil {
+		return r, err
+	}
+	for _, sh := range shifts {
+		rs := ReportShift{DomainShift: sh}
+		if sh.Paid || sh.PaidAmount > 0 {
+			payouts, err := s.Shifts.GetShiftPayouts(sh.ID)
+			if err != nil {
+				return r, err
+			}
+			for _, p := range payouts {
+				rs.PayoutIDs = append(rs.PayoutIDs, p.ID)
+			}
+		}
+		r.Shifts = append(r.Shifts, rs)
+	}
+	r.Payouts, err = s.Shifts.GetPayouts(employeeID, from, to)
+	return r, err
+}
+
+// Форматы выгрузки.
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var ErrUnknownFormat = errors.New("неизвестный формат выгрузки")
+
+// Export собирает выгрузку в воркер-пуле, чтобы не держать обработчик
+// Telegram. Заголовки и статусы — на языке lang.
+func (s *ExportService) Export(format string, employeeID int, from, to time.Time, lang i18n.Lang) ([]byte, error) {
+	var write func(io.Writer, Report, i18n.Lang) error
+	switch format {
+	case FormatCSV:
+		write = WriteCSV
+	case FormatXLSX:
+		write = WriteXLSX
+	default:
+		return nil, ErrUnknownFormat
+	}
+	res, err := s.Async.SubmitAsync(func() (any, error) {
+		r, err := s.BuildReport(employeeID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := write(&buf, r, lang); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.([]byte), nil
+}
+
+// WriteCSV пишет смены и выплаты одной таблицей; колонка record
+// отличает строки смен (shift) от строк выплат (payout). Заголовок — на
+// языке l, значения — коды, чтобы файл читался /import на любом языке.
+// В начале файла — UTF-8 BOM, чтобы Excel правильно показал кириллицу.
+func WriteCSV(w io.Writer, r Report, l i18n.Lang) error {
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(strings.Split(l.T("export.csv_header"), ",")); err != nil {
+		return err
+	}
+	for _, sh := range r.Shifts {
+		paid := "no"
+		switch {
+		case sh.Paid:
+			paid = "yes"
+		case sh.PaidAmount > 0:
+			paid = "partial"
+		}
+		refs := make([]string, 0, len(sh.PayoutIDs))
+		for _, id := range sh.PayoutIDs {
+			refs = append(refs, "#"+strconv.Itoa(id))
+		}
+		note := ""
+		if sh.Hourly != nil {
+			note = domain.FormatClock(sh.Hourly.Start) + "-" + domain.FormatClock(sh.Hourly.End)
+		}
+		if err := cw.Write([]string{
+			"shift", sh.Date.Format("2006-01-02"), sh.Amount.String(), paid, strings.Join(refs, " "), note, sh.Status, string(sh.Currency.OrDefault()),
+		}); err != nil {
+			return err
+		}
+	}
+	for _, p := range r.Payouts {
+		if err := cw.Write([]string{
+			"payout", p.Date.Format("2006-01-02"), p.Amount.String(), "", "#" + strconv.Itoa(p.ID), p.Note, p.Status,
+			string(p.Currency.OrDefault()),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Ключи каталога для статусов смен и выплат в XLSX.
+var shiftStatusKeys = map[string]string{
+	domain.ShiftPending:  "export.shift_pending",
+	domain.ShiftApproved: "export.shift_approved",
+	domain.ShiftRejected: "export.shift_rejected",
+}
+
+var payoutStatusKeys = map[string]string{
+	domain.PayoutUnconfirmed: "export.payout_unconfirmed",
+	domain.PayoutConfirmed:   "export.payout_confirmed",
+	domain.PayoutDisputed:    "export.payout_disputed",
+}
+
+// WriteXLSX пишет книгу из трёх листов: смены, выплаты и помесячная
+// сводка. Даты и суммы — настоящие числовые ячейки, а не текст; названия
+// листов, заголовки и статусы — на языке l.
+func WriteXLSX(w io.Writer, r Report, l i18n.Lang) error {
+	book := xlsx.New()
+	header := func(key string) xlsx.Cell { return xlsx.Header(l.T(key)) }
+
+	shifts := book.AddSheet(l.T("export.sheet_shifts"))
+	shifts.AddRow(header("export.col_date"), header("export.col_amount"), header("export.col_paid"),
+		header("export.col_remaining"), header("export.col_payouts"), header("export.col_time"), header("export.col_status"),
+		header("export.col_currency"))
+	for _, sh := range r.Shifts {
+		refs := make([]string, 0, len(sh.PayoutIDs))
+		for _, id := range sh.PayoutIDs {
+			refs = append(refs, "#"+strconv.Itoa(id))
+		}
+		times := ""
+		if sh.Hourly != nil {
+			times = domain.FormatClock(sh.Hourly.Start) + "-" + domain.FormatClock(sh.Hourly.End)
+		}
+		// остаток есть только у подтверждённой смены, у остальных
+		// колонка пустая, а причина видна в статусе
+		paid := shiftPaid(sh.DomainShift)
+		remaining := xlsx.String("")
+		if sh.Approved() {
+			remaining = xlsx.Money((sh.Amount - paid).Float())
+		}
+		shifts.AddRow(xlsx.Date(sh.Date), xlsx.Money(sh.Amount.Float()), xlsx.Money(paid.Float()),
+			remaining, xlsx.String(strings.Join(refs, " ")), xlsx.String(times),
+			xlsx.String(l.T(shiftStatusKeys[sh.Status])), xlsx.String(string(sh.Currency.OrDefault())))
+	}
+
+	payouts := book.AddSheet(l.T("export.sheet_payouts"))
+	payouts.AddRow(header("export.col_no"), header("export.col_date"), header("export.col_amount"), header("export.col_note"),
+		header("export.col_currency"), header("export.col_status"))
+	for _, p := range r.Payouts {
+		payouts.AddRow(xlsx.Int(p.ID), xlsx.Date(p.Date), xlsx.Money(p.Amount.Float()), xlsx.String(PayoutNote(l, p.Note)),
+			xlsx.String(string(p.Currency.OrDefault())), xlsx.String(l.T(payoutStatusKeys[p.Status])))
+	}
+
+	summary := book.AddSheet(l.T("export.sheet_summary"))
+	summary.AddRow(header("export.col_month"), header("export.col_currency"), header("export.col_shifts"), header("export.col_earned"),
+		header("export.col_pending"), header("export.col_paid"), header("export.col_outstanding"))
+	for _, m := range monthlySummary(r) {
+		summary.AddRow(xlsx.Month(m.Month), xlsx.String(string(m.Currency)), xlsx.Int(m.Shifts), xlsx.Money(m.Earned.Float()),
+			xlsx.Money(m.Pending.Float()), xlsx.Money(m.PaidOut.Float()), xlsx.Money(m.Outstanding.Float()))
+	}
+
+	return book.Write(w)
+}
+
+// shiftPaid — сколько по смене выплачено по журналу выплат, независимо
+// от её статуса.
+func shiftPaid(sh domain.DomainShift) money.Amount {
+	if sh.Paid {
+		return sh.Amount
+	}
+	return sh.PaidAmount
+}
+
+type monthTotals struct {
+	Month       time.Time
+	Currency    money.Currency
+	Shifts      int
+	Earned      money.Amount
+	PaidOut     money.Amount
+	Outstanding money.Amount
+	// Pending — смены, ждущие подтверждения: в Earned и Outstanding
+	// они не входят.
+	Pending money.Amount
+}
+
+// monthlySummary считает итоги по каждому месяцу периода, включая пустые,
+// отдельной строкой для каждой валюты отчёта.
+func monthlySummary(r Report) []monthTotals {
+	seen := map[money.Currency]bool{}
+	for _, sh := range r.Shifts {
+		seen[sh.Currency.OrDefault()] = true
+	}
+	for _, p := range r.Payouts {
+		seen[p.Currency.OrDefault()] = true
+	}
+	if len(seen) == 0 {
+		seen[money.DefaultCurrency] = true
+	}
+	var currencies []money.Currency
+	for _, c := range money.Currencies() {
+		if seen[c] {
+			currencies = append(currencies, c)
+		}
+	}
+
+	type key struct {
+		month    time.Time
+		currency money.Currency
+	}
+	var months []monthTotals
+	index := map[key]int{}
+	for m := time.Date(r.From.Year(), r.From.Month(), 1, 0, 0, 0, 0, time.UTC); !m.After(r.To); m = m.AddDate(0, 1, 0) {
+		for _, c := range currencies {
+			index[key{m, c}] = len(months)
+			months = append(months, monthTotals{Month: m, Currency: c})
+		}
+	}
+	at := func(t time.Time, c money.Currency) *monthTotals {
+		i, ok := index[key{time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC), c.OrDefault()}]
+		if !ok {
+			return nil
+		}
+		return &months[i]
+	}
+	for _, sh := range r.Shifts {
+		m := at(sh.Date, sh.Currency)
+		switch {
+		case m == nil:
+		case sh.Approved():
+			m.Shifts++
+			m.Earned += sh.Amount
+			m.Outstanding += sh.Amount - shiftPaid(sh.DomainShift)
+		case sh.Status == domain.ShiftPending:
+			m.Pending += sh.Amount
+		}
+	}
+	for _, p := range r.Payouts {
+		if m := at(p.Date, p.Currency); m != nil && p.Counted() {
+			m.PaidOut += p.Amount
+		}
+	}
+	return months
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/i18n"
+	"salary-bot/pkg/money"
+)
+
+// readSheet возвращает значения ячеек листа n книги XLSX по адресам.
+func readSheet(t *testing.T, book []byte, n int) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(book), int64(len(book)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := zr.Open("xl/worksheets/sheet" + string(rune('0'+n)) + ".xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sheet struct {
+		Cells []struct {
+			Ref   string `xml:"r,attr"`
+			Value string `xml:"v"`
+			Text  string `xml:"is>t"`
+		} `xml:"sheetData>row>c"`
+	}
+	if err := xml.Unmarshal(data, &sheet); err != nil {
+		t.Fatal(err)
+	}
+	cells := map[string]string{}
+	for _, c := range sheet.Cells {
+		cells[c.Ref] = c.Value + c.Text
+	}
+	return cells
+}
+
+func TestWriteXLSXUnapprovedShifts(t *testing.T) {
+	r := Report{From: date("2024-03-01"), To: date("2024-03-31"), Shifts: []ReportShift{
+		{DomainShift: domain.DomainShift{Date: date("2024-03-01"), Amount: 100000, PaidAmount: 40000, Status: domain.ShiftApproved, Currency: money.RUB}},
+		{DomainShift: domain.DomainShift{Date: date("2024-03-02"), Amount: 50000, Status: domain.ShiftPending, Currency: money.RUB}},
+		{DomainShift: domain.DomainShift{Date: date("2024-03-03"), Amount: 30000, Status: domain.ShiftRejected, Currency: money.RUB}},
+	}, Payouts: []domain.Payout{
+		{ID: 1, Date: date("2024-03-01"), Amount: 40000, Note: domain.PayoutNoteLegacy, Status: domain.PayoutConfirmed, Currency: money.RUB},
+	}}
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, r, i18n.EN); err != nil {
+		t.Fatal(err)
+	}
+
+	shifts := readSheet(t, buf.Bytes(), 1)
+	// колонки: дата, сумма, выплачено, остаток, …, статус; неподтверждённые
+	// смены не выглядят выплаченными
+	want := map[string]string{
+		"G1": "Status",
+		"C2": "400", "D2": "600", "G2": "approved",
+		"C3": "0", "D3": "", "G3": "pending approval",
+		"C4": "0", "D4": "", "G4": "rejected",
+	}
+	for ref, v := range want {
+		if shifts[ref] != v {
+			t.Errorf("shifts %s = %q, want %q", ref, shifts[ref], v)
+		}
+	}
+
+	// служебный комментарий и статус выплаты — на языке выгрузки
+	payouts := readSheet(t, buf.Bytes(), 2)
+	if payouts["D2"] != "marked paid before the payout ledger" || payouts["F2"] != "confirmed" {
+		t.Errorf("payout row = %q, %q", payouts["D2"], payouts["F2"])
+	}
+
+	summary := readSheet(t, buf.Bytes(), 3)
+	// смен, начислено, ждёт подтверждения, выплачено, остаток
+	want = map[string]string{"C2": "1", "D2": "1000", "E2": "500", "F2": "400", "G2": "600"}
+	for ref, v := range want {
+		if summary[ref] != v {
+			t.Errorf("summary %s = %q, want %q", ref, summary[ref], v)
+		}
+	}
+}
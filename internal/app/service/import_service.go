@@ -0,0 +1,354 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/money"
+)
+
+// MaxImportRows ограничивает размер одного импорта.
+const MaxImportRows = 1000
+
+var (
+	ErrImportEmpty   = errors.New("в файле нет строк со сменами")
+	ErrImportTooBig  = fmt.Errorf("слишком много строк, максимум %d", MaxImportRows)
+	ErrImportColumns = errors.New("не найдены колонки с датой и суммой")
+)
+
+// ImportRow — одна распознанная строка файла.
+type ImportRow struct {
+	Line   int
+	Date   time.Time
+	Amount money.Amount
+	Paid   bool
+	// Currency — из колонки «валюта»; пустая — валюта оплаты сотрудника.
+	Currency money.Currency
+}
+
+// ImportRowError — строка файла, которую не удалось разобрать. Текст
+// ошибки собирается на языке читателя: Key — ключ каталога i18n, Args —
+// его аргументы.
+type ImportRowError struct {
+	Line int
+	Key  string
+	Args []any
+}
+
+// ImportPreview — результат разбора файла до записи в базу. Valid будут
+// добавлены при подтверждении, Duplicates и Errors — пропущены.
+type ImportPreview struct {
+	Valid      []ImportRow
+	Duplicates []ImportRow
+	Errors     []ImportRowError
+}
+
+var importDateLayouts = []string{"2006-01-02", "02.01.2006", "02.01.06", "02/01/2006", "2.1.2006"}
+
+// Заголовки, по которым ищутся колонки. Без заголовка порядок такой:
+// дата, сумма, выплачено (необязательно).
+var importHeaders = map[string]string{
+	"date": "date", "дата": "date",
+	"amount": "amount", "сумма": "amount",
+	"paid": "paid", "выплачено": "paid", "оплачено": "paid",
+	"record": "record", "запись": "record",
+	"currency": "currency", "валюта": "currency",
+}
+
+// ParseShiftsCSV разбирает CSV со сменами. Разделитель (запятая или точка
+// с запятой) определяется по первой строке; файл, выгруженный через
+// /export, читается как есть — строки выплат в нём пропускаются.
+func ParseShiftsCSV(r io.Reader) ([]ImportRow, []ImportRowError, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	text := strings.TrimPrefix(string(data), "\uFEFF")
+	first, _, _ := strings.Cut(text, "\n")
+
+	cr := csv.NewReader(strings.NewReader(text))
+	if strings.Count(first, ";") > strings.Count(first, ",") {
+		cr.Comma = ';'
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	// csv.Reader пропускает пустые строки, поэтому номер строки файла
+	// берётся из FieldPos, а не из индекса записи
+	var (
+		records [][]string
+		lines   []int
+	)
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		records = append(records, rec)
+		lines = append(lines, line)
+	}
+
+	cols := map[string]int{"date": 0, "amount": 1, "paid": 2, "record": -1, "currency": -1}
+	start := 0
+	if len(records) > 0 && !looksLikeDate(records[0]) {
+		cols = map[string]int{"date": -1, "amount": -1, "paid": -1, "record": -1, "currency": -1}
+		for i, name := range records[0] {
+			if key, ok := importHeaders[strings.ToLower(strings.TrimSpace(name))]; ok && cols[key] < 0 {
+				cols[key] = i
+			}
+		}
+		if cols["date"] < 0 || cols["amount"] < 0 {
+			return nil, nil, ErrImportColumns
+		}
+		start = 1
+	}
+
+	var (
+		rows []ImportRow
+		errs []ImportRowError
+	)
+	for i := start; i < len(records); i++ {
+		rec, line := records[i], lines[i]
+		if isBlank(rec) || cols["record"] >= 0 && field(rec, cols["record"]) != "shift" {
+			continue
+		}
+		if len(rows)+len(errs) >= MaxImportRows {
+			return nil, nil, ErrImportTooBig
+		}
+		row, rowErr := parseImportRow(rec, cols)
+		if rowErr != nil {
+			rowErr.Line = line
+			errs = append(errs, *rowErr)
+			continue
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 && len(errs) == 0 {
+		return nil, nil, ErrImportEmpty
+	}
+	return rows, errs, nil
+}
+
+func parseImportRow(rec []string, cols map[string]int) (ImportRow, *ImportRowError) {
+	var row ImportRow
+	date, ok := parseImportDate(field(rec, cols["date"]))
+	if !ok {
+		return row, rowError("import.err_date", field(rec, cols["date"]))
+	}
+	// в самом восточном поясе (UTC+14) завтра наступает раньше всего
+	if date.After(time.Now().Add(14 * time.Hour)) {
+		return row, rowError("import.err_future")
+	}
+	row.Date = date
+	amount, err := money.Parse(field(rec, cols["amount"]))
+	if err != nil || amount <= 0 {
+		return row, rowError("import.err_amount", field(rec, cols["amount"]))
+	}
+	row.Amount = amount
+	if row.Paid, ok = parseImportPaid(field(rec, cols["paid"])); !ok {
+		return row, rowError("import.err_paid", field(rec, cols["paid"]))
+	}
+	if code := field(rec, cols["currency"]); code != "" {
+		if row.Currency, err = money.ParseCurrency(code); err != nil {
+			return row, rowError("import.err_currency", code)
+		}
+	}
+	return row, nil
+}
+
+func rowError(key string, args ...any) *ImportRowError {
+	return &ImportRowError{Key: key, Args: args}
+}
+
+func parseImportDate(s string) (time.Time, bool) {
+	for _, layout := range importDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseImportPaid(s string) (bool, bool) {
+	switch strings.ToLower(s) {
+	case "", "no", "нет", "0", "false", "partial":
+		return false, true
+	case "yes", "да", "1", "true", "+":
+		return true, true
+	}
+	return false, false
+}
+
+func looksLikeDate(rec []string) bool {
+	_, ok := parseImportDate(field(rec, 0))
+	return ok
+}
+
+func field(rec []string, i int) string {
+	if i < 0 || i >= len(rec) {
+		return ""
+	}
+	return strings.TrimSpace(rec[i])
+}
+
+func isBlank(rec []string) bool {
+	for _, f := range rec {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// PreviewImport разбирает файл и отделяет дубликаты: смены с той же
+// датой, суммой и валютой, что уже есть у сотрудника или выше в файле.
+func (s *ShiftServiceImpl) PreviewImport(employeeID int, r io.Reader) (ImportPreview, error) {
+	rows, errs, err := ParseShiftsCSV(r)
+	if err != nil {
+		return ImportPreview{}, err
+	}
+	p := ImportPreview{Errors: errs}
+	if len(rows) == 0 {
+		return p, nil
+	}
+	from, to := rows[0].Date, rows[0].Date
+	for _, row := range rows {
+		if row.Date.Before(from) {
+			from = row.Date
+		}
+		if row.Date.After(to) {
+			to = row.Date
+		}
+	}
+	existing, err := s.Repo.GetShifts(employeeID, from, to)
+	if err != nil {
+		return p, err
+	}
+	seen := make(map[string]bool, len(existing)+len(rows))
+	key := func(d time.Time, a money.Amount, c money.Currency) string {
+		return d.Format("2006-01-02") + "|" + a.String() + "|" + string(c.OrDefault())
+	}
+	for _, sh := range existing {
+		seen[key(sh.Date, sh.Amount, sh.Currency)] = true
+	}
+	defaultCurrency := s.EmployeeCurrency(employeeID)
+	for _, row := range rows {
+		currency := row.Currency
+		if currency == "" {
+			currency = defaultCurrency
+		}
+		k := key(row.Date, row.Amount, currency)
+		if seen[k] {
+			p.Duplicates = append(p.Duplicates, row)
+			continue
+		}
+		seen[k] = true
+		p.Valid = append(p.Valid, row)
+	}
+	return p, nil
+}
+
+// ImportShifts добавляет подтверждённые строки одной транзакцией. Строки
+// с признаком «выплачено» покрываются выплатами в той же транзакции — по
+// одной на валюту и месяц, датой последней такой смены месяца. Выплаты
+// подтверждены, если смены не ждут менеджера, иначе их подтверждает
+// ApprovePending вместе со сменами.
+func (s *ShiftServiceImpl) ImportShifts(employeeID int, rows []ImportRow) (int, error) {
+	status, err := s.newShiftStatus()
+	if err != nil {
+		return 0, err
+	}
+	payoutStatus := domain.PayoutConfirmed
+	if status != domain.ShiftApproved {
+		payoutStatus = domain.PayoutUnconfirmed
+	}
+	defaultCurrency := s.EmployeeCurrency(employeeID)
+	shifts := make([]domain.DomainShift, 0, len(rows))
+	var payouts []domain.BatchPayout
+	group := make(map[string]int)
+	for i, row := range rows {
+		currency := row.Currency
+		if currency == "" {
+			currency = defaultCurrency
+		}
+		shifts = append(shifts, domain.DomainShift{
+			EmployeeID: employeeID,
+			Date:       row.Date,
+			Amount:     row.Amount,
+			Status:     status,
+			Currency:   currency,
+		})
+		if !row.Paid {
+			continue
+		}
+		k := string(currency) + "|" + row.Date.Format("2006-01")
+		j, ok := group[k]
+		if !ok {
+			j = len(payouts)
+			group[k] = j
+			payouts = append(payouts, domain.BatchPayout{Payout: domain.Payout{
+				EmployeeID: employeeID,
+				Note:       domain.PayoutNoteImport,
+				RecordedBy: s.actor,
+				Status:     payoutStatus,
+				RecordedAt: time.Now(),
+				Currency:   currency,
+			}})
+		}
+		p := &payouts[j]
+		p.Payout.Amount += row.Amount
+		if row.Date.After(p.Payout.Date) {
+			p.Payout.Date = row.Date
+		}
+		p.Shifts = append(p.Shifts, i)
+	}
+	ids, _, err := s.Repo.AddShifts(shifts, payouts)
+	return len(ids), err
+}
+
+// EncodeImportRows упаковывает строки для хранения в состоянии диалога:
+// "дата;копейки;0|1;валюта" через перевод строки.
+func EncodeImportRows(rows []ImportRow) string {
+	var b strings.Builder
+	for _, row := range rows {
+		paid := "0"
+		if row.Paid {
+			paid = "1"
+		}
+		b.WriteString(row.Date.Format("2006-01-02") + ";" + strconv.FormatInt(row.Amount.Minor(), 10) + ";" + paid + ";" + string(row.Currency) + "\n")
+	}
+	return b.String()
+}
+
+func DecodeImportRows(s string) ([]ImportRow, error) {
+	var rows []ImportRow
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.Split(line, ";")
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("повреждённая строка импорта %q", line)
+		}
+		date, err := time.Parse("2006-01-02", parts[0])
+		if err != nil {
+			return nil, err
+		}
+		minor, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, ImportRow{Date: date, Amount: money.FromMinor(minor), Paid: parts[2] == "1", Currency: money.Currency(parts[3])})
+	}
+	return rows, nil
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/i18n"
+	"salary-bot/pkg/money"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestParseShiftsCSVHeaders(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []ImportRow
+	}{
+		{
+			name: "без заголовка",
+			in:   "2024-03-01,1500,yes\n01.03.2024,2000.50\n",
+			want: []ImportRow{
+				{Line: 1, Date: date("2024-03-01"), Amount: 150000, Paid: true},
+				{Line: 2, Date: date("2024-03-01"), Amount: 200050},
+			},
+		},
+		{
+			name: "русский заголовок, точка с запятой, колонки в другом порядке",
+			in:   "\uFEFFСумма;Валюта;Дата;Выплачено\n1 500,50;usd;02.03.2024;да\n",
+			want: []ImportRow{
+				{Line: 2, Date: date("2024-03-02"), Amount: 150050, Paid: true, Currency: money.USD},
+			},
+		},
+		{
+			name: "пустые строки пропускаются",
+			in:   "date,amount\n\n2024-03-01,10\n,\n",
+			want: []ImportRow{{Line: 3, Date: date("2024-03-01"), Amount: 1000}},
+		},
+		{
+			name: "выгрузка /export: строки выплат пропускаются",
+			in: "record,date,amount,paid,payout_ref,note,status,currency\n" +
+				"shift,2024-03-01,1500.00,partial,#1,,approved,RUB\n" +
+				"payout,2024-03-05,500.00,,#1,,,RUB\n",
+			want: []ImportRow{{Line: 2, Date: date("2024-03-01"), Amount: 150000, Currency: money.RUB}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, errs, err := ParseShiftsCSV(strings.NewReader(tt.in))
+			if err != nil || len(errs) != 0 {
+				t.Fatalf("err = %v, row errors = %v", err, errs)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("rows = %+v, want %+v", rows, tt.want)
+			}
+			for i := range rows {
+				if rows[i] != tt.want[i] {
+					t.Errorf("row %d = %+v, want %+v", i, rows[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseShiftsCSVErrors(t *testing.T) {
+	future := time.Now().AddDate(0, 0, 3).Format("2006-01-02")
+	in := "date,amount,paid,currency\n" +
+		"2024-03-01,1500,no,RUB\n" +
+		"2024-13-01,1500\n" +
+		"2024-03-02,-5\n" +
+		"2024-03-03,abc\n" +
+		future + ",100\n" +
+		"2024-03-04,100,maybe\n" +
+		"2024-03-05,100,no,XYZ\n"
+	rows, errs, err := ParseShiftsCSV(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].Line != 2 {
+		t.Errorf("rows = %+v", rows)
+	}
+	want := []struct {
+		line int
+		key  string
+	}{
+		{3, "import.err_date"},
+		{4, "import.err_amount"},
+		{5, "import.err_amount"},
+		{6, "import.err_future"},
+		{7, "import.err_paid"},
+		{8, "import.err_currency"},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("errors = %+v", errs)
+	}
+	for i, w := range want {
+		if errs[i].Line != w.line || errs[i].Key != w.key {
+			t.Errorf("error %d = %+v, want line %d %s", i, errs[i], w.line, w.key)
+		}
+		if !i18n.Has(errs[i].Key) {
+			t.Errorf("error %d: no message %q", i, errs[i].Key)
+		}
+	}
+}
+
+func TestParseShiftsCSVFileErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want error
+	}{
+		{"пустой файл", "", ErrImportEmpty},
+		{"только заголовок", "date,amount\n", ErrImportEmpty},
+		{"нет колонки суммы", "date,paid\n2024-03-01,yes\n", ErrImportColumns},
+		{"слишком много строк", strings.Repeat("2024-03-01,100\n", MaxImportRows+1), ErrImportTooBig},
+	}
+	for _, tt := range tests {
+		if _, _, err := ParseShiftsCSV(strings.NewReader(tt.in)); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	r := Report{Shifts: []ReportShift{
+		{DomainShift: domain.DomainShift{Date: date("2024-03-01"), Amount: 150050, Paid: true, Status: domain.ShiftApproved, Currency: money.RUB}, PayoutIDs: []int{1}},
+		{DomainShift: domain.DomainShift{Date: date("2024-03-02"), Amount: 2000, Status: domain.ShiftApproved, Currency: money.USD}},
+	}, Payouts: []domain.Payout{{ID: 1, Date: date("2024-03-05"), Amount: 150050, Currency: money.RUB}}}
+	// выгрузка на любом языке читается импортом
+	for _, l := range i18n.Langs() {
+		var buf bytes.Buffer
+		if err := WriteCSV(&buf, r, l); err != nil {
+			t.Fatal(err)
+		}
+		rows, errs, err := ParseShiftsCSV(&buf)
+		if err != nil || len(errs) != 0 || len(rows) != 2 {
+			t.Fatalf("%s: rows = %+v, errors = %v, err = %v", l, rows, errs, err)
+		}
+		for i, sh := range r.Shifts {
+			got := rows[i]
+			if !got.Date.Equal(sh.Date) || got.Amount != sh.Amount || got.Paid != sh.Paid || got.Currency != sh.Currency {
+				t.Errorf("%s: row %d = %+v, want shift %+v", l, i, got, sh.DomainShift)
+			}
+		}
+	}
+}
+
+func TestEncodeDecodeImportRows(t *testing.T) {
+	rows := []ImportRow{
+		{Date: date("2024-03-01"), Amount: 150050, Paid: true, Currency: money.USD},
+		{Date: date("2024-03-02"), Amount: 1},
+	}
+	got, err := DecodeImportRows(EncodeImportRows(rows))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(rows) {
+		t.Fatalf("decoded %+v", got)
+	}
+	for i := range rows {
+		if got[i] != rows[i] {
+			t.Errorf("row %d = %+v, want %+v", i, got[i], rows[i])
+		}
+	}
+	if _, err := DecodeImportRows("2024-03-01;100;1\n"); err == nil {
+		t.Error("truncated row: want error")
+	}
+}
+
+// importRepo — смены в памяти для проверки превью и записи импорта.
+// Выплаты импорта, кроме payouts, записываются в ledger, если он задан.
+type importRepo struct {
+	domain.ShiftRepo
+	shifts  []domain.DomainShift
+	payouts []domain.BatchPayout
+	ledger  *payoutRepo
+}
+
+func (r *importRepo) GetShifts(employeeID int, from, to time.Time) ([]domain.DomainShift, error) {
+	var out []domain.DomainShift
+	for _, sh := range r.shifts {
+		if sh.EmployeeID == employeeID && !sh.Date.Before(from) && !sh.Date.After(to) {
+			out = append(out, sh)
+		}
+	}
+	return out, nil
+}
+
+func (r *importRepo) AddShifts(shifts []domain.DomainShift, payouts []domain.BatchPayout) ([]int, []int, error) {
+	ids := make([]int, len(shifts))
+	for i, sh := range shifts {
+		sh.ID = len(r.shifts) + 1
+		r.shifts = append(r.shifts, sh)
+		ids[i] = sh.ID
+	}
+	r.payouts = append(r.payouts, payouts...)
+	payoutIDs := make([]int, len(payouts))
+	for i, p := range payouts {
+		if r.ledger != nil {
+			payoutIDs[i] = r.ledger.add(p.Payout, p.Allocations(shifts, ids))
+		}
+	}
+	return ids, payoutIDs, nil
+}
+
+func (r *importRepo) ApproveShifts(ids, payoutIDs []int) error {
+	for _, id := range ids {
+		r.shifts[id-1].Status = domain.ShiftApproved
+	}
+	for _, id := range payoutIDs {
+		if err := r.ledger.UpdatePayoutStatus(id, domain.PayoutConfirmed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestPreviewAndImport(t *testing.T) {
+	repo := &importRepo{shifts: []domain.DomainShift{
+		{ID: 1, EmployeeID: 7, Date: date("2024-03-01"), Amount: 150000, Currency: money.RUB},
+	}}
+	s := &ShiftServiceImpl{Repo: repo}
+	in := "date,amount,paid,currency\n" +
+		"2024-03-01,1500,no,\n" + // дубликат существующей смены
+		"2024-03-01,1500,no,USD\n" + // та же сумма в другой валюте — не дубликат
+		"2024-03-02,1000,yes,\n" +
+		"2024-03-02,1000,yes,\n" + // дубликат строки выше
+		"2024-03-20,500,yes,\n" +
+		"2024-04-01,700,yes,\n" +
+		"bad,1\n"
+	p, err := s.PreviewImport(7, strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Valid) != 4 || len(p.Duplicates) != 2 || len(p.Errors) != 1 {
+		t.Fatalf("preview = %+v", p)
+	}
+	if p.Duplicates[0].Line != 2 || p.Duplicates[1].Line != 5 {
+		t.Errorf("duplicates = %+v", p.Duplicates)
+	}
+
+	// превью хранится в состоянии диалога до подтверждения
+	rows, err := DecodeImportRows(EncodeImportRows(p.Valid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := s.ImportShifts(7, rows)
+	if err != nil || n != 4 {
+		t.Fatalf("ImportShifts = %d, %v", n, err)
+	}
+	if len(repo.shifts) != 5 {
+		t.Fatalf("shifts = %+v", repo.shifts)
+	}
+	// выплаченные строки — по выплате на месяц и валюту
+	if len(repo.payouts) != 2 {
+		t.Fatalf("payouts = %+v", repo.payouts)
+	}
+	march, april := repo.payouts[0], repo.payouts[1]
+	if march.Payout.Amount != 150000 || !march.Payout.Date.Equal(date("2024-03-20")) || len(march.Shifts) != 2 {
+		t.Errorf("March payout = %+v", march)
+	}
+	if april.Payout.Amount != 70000 || april.Payout.Status != domain.PayoutConfirmed || len(april.Shifts) != 1 {
+		t.Errorf("April payout = %+v", april)
+	}
+	for _, sh := range repo.shifts[1:] {
+		if sh.Paid || sh.Currency == "" {
+			t.Errorf("imported shift = %+v", sh)
+		}
+	}
+}
+
+func TestImportThenApprovePending(t *testing.T) {
+	const (
+		manager  = 1
+		employee = 2
+	)
+	ledger := &payoutRepo{}
+	// выплата, записанная вручную, подтверждается отдельно
+	manual := ledger.add(domain.Payout{EmployeeID: employee, RecordedBy: employee, Status: domain.PayoutUnconfirmed}, nil)
+	repo := &importRepo{ledger: ledger}
+	s := &ShiftServiceImpl{Repo: repo, Payouts: ledger, Employees: &employeeRepo{employees: []domain.Employee{
+		{ID: manager, Role: domain.RoleManager}, {ID: employee, Role: domain.RoleEmployee},
+	}}}
+
+	rows := []ImportRow{
+		{Date: date("2024-03-01"), Amount: 100000, Paid: true},
+		{Date: date("2024-03-02"), Amount: 50000},
+		{Date: date("2024-04-01"), Amount: 70000, Paid: true},
+	}
+	if n, err := s.As(employee).ImportShifts(employee, rows); err != nil || n != 3 {
+		t.Fatalf("ImportShifts = %d, %v", n, err)
+	}
+	for _, p := range ledger.payouts {
+		if p.Status != domain.PayoutUnconfirmed {
+			t.Fatalf("payout before approval = %+v", p)
+		}
+	}
+
+	if _, err := s.As(employee).ApprovePending(employee); !errors.Is(err, ErrNotManager) {
+		t.Errorf("approve by employee: err = %v", err)
+	}
+	if n, err := s.As(manager).ApprovePending(employee); err != nil || n != 3 {
+		t.Fatalf("ApprovePending = %d, %v", n, err)
+	}
+	for _, sh := range repo.shifts {
+		if sh.Status != domain.ShiftApproved {
+			t.Errorf("shift after approval = %+v", sh)
+		}
+	}
+	for id, p := range ledger.payouts {
+		want := domain.PayoutConfirmed
+		if id == manual {
+			want = domain.PayoutUnconfirmed
+		}
+		if p.Status != want {
+			t.Errorf("payout %d status = %q, want %q", id, p.Status, want)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"os"
+	"time"
+
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/i18n"
+	"salary-bot/pkg/money"
+)
+
+//go:embed templates/payslip.html
+var defaultPayslipTemplate string
+
+// payslipFuncs — функции, доступные в шаблоне расчётного листка: подписи
+// (t), месяцы, даты и суммы оформляются по правилам языка l.
+func payslipFuncs(l i18n.Lang) template.FuncMap {
+	return template.FuncMap{
+		"t":        l.T,
+		"lang":     func() string { return string(l) },
+		"month":    func(t time.Time) string { return l.Month(t.Month()) + " " + t.Format("2006") },
+		"date":     l.Date,
+		"datetime": func(t time.Time) string { return l.Date(t) + " " + t.Format("15:04") },
+		"clock":    domain.FormatClock,
+		"money":    l.Money,
+		"totals":   l.Totals,
+		"note":     func(note string) string { return PayoutNote(l, note) },
+	}
+}
+
+// payoutNotes — ключи каталога для служебных комментариев выплат.
+var payoutNotes = map[string]string{
+	domain.PayoutNoteImport: "payout.note_import",
+	domain.PayoutNoteLegacy: "payout.note_legacy",
+}
+
+// PayoutNote переводит служебный комментарий выплаты на язык l; прочие
+// комментарии возвращаются как есть.
+func PayoutNote(l i18n.Lang, note string) string {
+	if key, ok := payoutNotes[note]; ok {
+		return l.T(key)
+	}
+	return note
+}
+
+// Payslip — данные расчётного листка за месяц; это же значение получает шаблон.
+type Payslip struct {
+	Employee    domain.Employee
+	Month       time.Time
+	Shifts      []domain.DomainShift
+	Adjustments []PayslipAdjustment
+	Payouts     []domain.Payout
+	// Opening — долг по зарплате на начало месяца, Closing — на конец.
+	// Итоги считаются по каждой валюте отдельно.
+	Opening, Earned, Paid, Closing money.Totals
+	GeneratedAt                    time.Time
+	// Lang — язык листка.
+	Lang i18n.Lang
+}
+
+// PayslipAdjustment — правка или удаление смены этого месяца из журнала аудита.
+type PayslipAdjustment struct {
+	Date        time.Time
+	ChangedAt   time.Time
+	Description string
+}
+
+type PayslipService struct {
+	Shifts    *ShiftServiceImpl
+	Employees *EmployeeService
+	Audit     *AuditService
+	tmpl      *template.Template
+}
+
+// NewPayslipService разбирает шаблон из templatePath или встроенный, если
+// путь пустой. Ошибка шаблона возвращается сразу, а не при первом листке.
+func NewPayslipService(shifts *ShiftServiceImpl, employees *EmployeeService, audit *AuditService, templatePath string) (*PayslipService, error) {
+	text := defaultPayslipTemplate
+	if templatePath != "" {
+		b, err := os.ReadFile(templatePath)
+		if err != nil {
+			return nil, err
+		}
+		text = string(b)
+	}
+	tmpl, err := template.New("payslip").Funcs(payslipFuncs(i18n.Default)).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &PayslipService{Shifts: shifts, Employees: employees, Audit: audit, tmpl: tmpl}, nil
+}
+
+func (s *PayslipService) Build(employeeID int, month time.Time, lang i18n.Lang) (Payslip, error) {
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, -1)
+	p := Payslip{Month: from, Lang: lang.OrDefault(),
+		Opening: money.Totals{}, Earned: money.Totals{}, Paid: money.Totals{}, Closing: money.Totals{}}
+
+	var err error
+	if p.Employee, err = s.Employees.GetEmployeeByID(employeeID); err != nil {
+		return p, err
+	}
+	p.GeneratedAt = p.Employee.Now()
+	epoch := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := from.AddDate(0, 0, -1)
+	shiftsBefore, err := s.Shifts.GetShifts(employeeID, epoch, before)
+	if err != nil {
+		return p, err
+	}
+	paidBefore, err := s.Shifts.GetPayouts(employeeID, epoch, before)
+	if err != nil {
+		return p, err
+	}
+	for _, sh := range approvedOnly(shiftsBefore) {
+		p.Opening.Add(sh.Currency, sh.Amount)
+	}
+	for _, pay := range countedOnly(paidBefore) {
+		p.Opening.Add(pay.Currency, -pay.Amount)
+	}
+
+	if p.Shifts, err = s.Shifts.GetShifts(employeeID, from, to); err != nil {
+		return p, err
+	}
+	p.Shifts = approvedOnly(p.Shifts)
+	for c, a := range p.Opening {
+		p.Closing.Add(c, a)
+	}
+	for _, sh := range p.Shifts {
+		p.Earned.Add(sh.Currency, sh.Amount)
+		p.Closing.Add(sh.Currency, sh.Amount)
+	}
+	if p.Payouts, err = s.Shifts.GetPayouts(employeeID, from, to); err != nil {
+		return p, err
+	}
+	p.Payouts = countedOnly(p.Payouts)
+	for _, pay := range p.Payouts {
+		p.Paid.Add(pay.Currency, pay.Amount)
+		p.Closing.Add(pay.Currency, -pay.Amount)
+	}
+
+	p.Adjustments, err = s.adjustments(p.Lang, employeeID, from, to)
+	return p, err
+}
+
+// Render строит листок и оформляет его на языке lang.
+func (s *PayslipService) Render(employeeID int, month time.Time, lang i18n.Lang) ([]byte, error) {
+	p, err := s.Build(employeeID, month, lang)
+	if err != nil {
+		return nil, err
+	}
+	// разобранный шаблон не исполняется, поэтому его можно клонировать
+	// и подменить функции под язык листка
+	tmpl, err := s.tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Funcs(payslipFuncs(p.Lang)).Execute(&buf, p); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// adjustments собирает из журнала аудита правки и удаления смен, дата
+// которых (до или после правки) попадает в месяц.
+func (s *PayslipService) adjustments(l i18n.Lang, employeeID int, from, to time.Time) ([]PayslipAdjustment, error) {
+	if s.Audit == nil {
+		return nil, nil
+	}
+	entries, err := s.Audit.EntityHistory(employeeID, domain.EntityShift)
+	if err != nil {
+		return nil, err
+	}
+	in := func(t time.Time) bool { return !t.Before(from) && !t.After(to) }
+	var adj []PayslipAdjustment
+	for _, e := range entries {
+		var before, after domain.DomainShift
+		_ = json.Unmarshal([]byte(e.Before), &before)
+		_ = json.Unmarshal([]byte(e.After), &after)
+		switch e.Action {
+		case domain.AuditUpdate:
+			if !in(before.Date) && !in(after.Date) {
+				continue
+			}
+			if before.Date.Equal(after.Date) && before.Amount == after.Amount {
+				// смена статуса подтверждения, а не корректировка
+				continue
+			}
+			a := PayslipAdjustment{Date: after.Date, ChangedAt: e.CreatedAt}
+			if !before.Date.Equal(after.Date) {
+				a.Description = l.T("audit.date_changed", l.Date(before.Date), l.Date(after.Date))
+			}
+			if before.Amount != after.Amount {
+				if a.Description != "" {
+					a.Description += ", "
+				}
+				a.Description += l.T("audit.amount_changed", l.Money(before.Currency, before.Amount), l.Money(after.Currency, after.Amount))
+			}
+			adj = append(adj, a)
+		case domain.AuditDelete:
+			if in(before.Date) {
+				adj = append(adj, PayslipAdjustment{
+					Date:        before.Date,
+					ChangedAt:   e.CreatedAt,
+					Description: l.T("payslip.shift_deleted", l.Money(before.Currency, before.Amount)),
+				})
+			}
+		}
+	}
+	return adj, nil
+}
+
+// countedOnly убирает оспоренные выплаты: сотрудник их не получил.
+func countedOnly(payouts []domain.Payout) []domain.Payout {
+	out := payouts[:0:0]
+	for _, p := range payouts {
+		if p.Counted() {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"time"
+
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/money"
+)
+
+type RateService struct {
+	Repo  domain.RateCardRepo
+	Audit *AuditService
+}
+
+// As возвращает сервис, изменения через который пишутся в журнал аудита от имени actor.
+func (s *RateService) As(actor int64) *RateService {
+	if s.Audit == nil {
+		return s
+	}
+	cp := *s
+	cp.Repo = auditedRateCardRepo{RateCardRepo: s.Repo, audit: s.Audit, actor: actor}
+	return &cp
+}
+
+func NewRateService(repo domain.RateCardRepo) *RateService {
+	return &RateService{Repo: repo}
+}
+
+func (s *RateService) SetEmployeeRate(employeeID int, amount money.Amount, from time.Time) error {
+	return s.Repo.AddRateCard(domain.RateCard{EmployeeID: employeeID, Amount: amount, EffectiveFrom: from})
+}
+
+func (s *RateService) SetRoleRate(role string, amount money.Amount, from time.Time) error {
+	return s.Repo.AddRateCard(domain.RateCard{Role: role, Amount: amount, EffectiveFrom: from})
+}
+
+// RateFor возвращает ставку сотрудника на дату. Для почасовых сотрудников
+// без карточки используется ставка из их профиля.
+func (s *RateService) RateFor(e domain.Employee, date time.Time) (money.Amount, bool, error) {
+	rc, ok, err := s.Repo.GetEffectiveRate(e.ID, e.Role, date)
+	if err != nil {
+		return 0, false, err
+	}
+	if ok {
+		return rc.Amount, true, nil
+	}
+	if e.IsHourly() && e.HourlyRate > 0 {
+		return e.HourlyRate, true, nil
+	}
+	return 0, false, nil
+}
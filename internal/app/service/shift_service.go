@@ -1,157 +1,682 @@
 package service
 
 import (
+	"errors"
 	"sort"
 	"time"
 
 	"salary-bot/internal/domain"
+	"salary-bot/pkg/money"
+)
+
+var (
+	ErrPayoutExceedsBalance = domain.ErrPayoutExceedsBalance
+	ErrCurrencyMismatch     = errors.New("в этой валюте нет невыплаченных смен")
+	ErrShiftPaid            = errors.New("смена уже оплачена, сначала отмените выплату")
+	ErrShiftReviewed        = errors.New("смена уже рассмотрена")
+	ErrPayoutReviewed       = errors.New("выплата уже подтверждена или оспорена")
+	ErrNotPayoutCounterpart = errors.New("подтвердить выплату может только другая сторона")
+	ErrPayoutConfirmed      = errors.New("выплата подтверждена обеими сторонами, отменить её нельзя")
+	ErrShiftApproved        = errors.New("смена подтверждена менеджером, изменить её может только менеджер")
+	ErrResetLocked          = errors.New("есть подтверждённые смены или выплаты, удалить их может только менеджер")
+)
+
+// Что ReversePayout делает с выплатой.
+const (
+	ReversalDelete  = "delete"
+	ReversalDispute = "dispute"
 )
 
 type ShiftServiceImpl struct {
-	Repo domain.ShiftRepo
+	Repo    domain.ShiftRepo
+	Payouts domain.PayoutRepo
+	Audit   *AuditService
+	// Employees нужен для подтверждения смен: пока в боте есть менеджеры,
+	// смены, добавленные не менеджером, ждут подтверждения. nil — все
+	// смены подтверждаются сразу.
+	Employees domain.EmployeeRepo
+	actor     int64
 }
 
-// ResetEmployeeData removes all shifts for a given employee.
+// As возвращает сервис, изменения через который выполняются от имени actor
+// (Telegram ID пользователя) и пишутся в журнал аудита.
+func (s *ShiftServiceImpl) As(actor int64) *ShiftServiceImpl {
+	cp := *s
+	cp.actor = actor
+	if s.Audit == nil {
+		return &cp
+	}
+	cp.Repo = auditedShiftRepo{ShiftRepo: s.Repo, payouts: s.Payouts, audit: s.Audit, actor: actor}
+	cp.Payouts = auditedPayoutRepo{PayoutRepo: s.Payouts, audit: s.Audit, actor: actor}
+	return &cp
+}
+
+// ResetEmployeeData removes all shifts and payouts for a given employee.
+// Пока в боте есть менеджеры, сотрудник не может стереть то, что они
+// подтвердили: сброс отказывает, если есть подтверждённые смены или
+// выплаты.
 func (s *ShiftServiceImpl) ResetEmployeeData(employeeID int) error {
-    return s.Repo.DeleteByEmployee(employeeID)
+	if err := s.checkReset(employeeID); err != nil {
+		return err
+	}
+	if err := s.Payouts.DeleteByEmployee(employeeID); err != nil {
+		return err
+	}
+	return s.Repo.DeleteByEmployee(employeeID)
 }
 
-func (s *ShiftServiceImpl) MarkShiftsPaidAmount(employeeID int, amount float64) error {
-	from := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
-	to := time.Now().AddDate(10, 0, 0)
+func (s *ShiftServiceImpl) checkReset(employeeID int) error {
+	status, err := s.newShiftStatus()
+	if err != nil || status == domain.ShiftApproved {
+		return err
+	}
+	from, to := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Now().AddDate(10, 0, 0)
 	shifts, err := s.Repo.GetShifts(employeeID, from, to)
 	if err != nil {
 		return err
 	}
-	
-	unpaid := make([]domain.DomainShift, 0, len(shifts))
 	for _, sh := range shifts {
-		if !sh.Paid {
-			unpaid = append(unpaid, sh)
+		if sh.Approved() {
+			return ErrResetLocked
 		}
 	}
-	if len(unpaid) == 0 || amount <= 0 {
-		return nil
+	payouts, err := s.Payouts.GetPayouts(employeeID, from, to)
+	if err != nil {
+		return err
 	}
-	
-	sort.Slice(unpaid, func(i, j int) bool {
-		if unpaid[i].Amount == unpaid[j].Amount {
-			return unpaid[i].Date.Before(unpaid[j].Date)
+	for _, p := range payouts {
+		if p.Status == domain.PayoutConfirmed {
+			return ErrResetLocked
 		}
-		return unpaid[i].Amount < unpaid[j].Amount
-	})
+	}
+	return nil
+}
+
+// MarkShiftsPaidAmount проводит выплату на произвольную сумму в валюте
+// currency: она распределяется по невыплаченным сменам в этой валюте
+// начиная с самой ранней, последняя смена может оказаться оплаченной
+// частично. Возвращает ID выплаты (0, если платить нечего).
+func (s *ShiftServiceImpl) MarkShiftsPaidAmount(employeeID int, amount money.Amount, currency money.Currency, recordedBy int64, note string) (int, error) {
+	if amount <= 0 {
+		return 0, nil
+	}
+	all, err := s.unpaidShifts(employeeID, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Now().AddDate(10, 0, 0))
+	if err != nil {
+		return 0, err
+	}
+	currency = currency.OrDefault()
+	unpaid := inCurrency(all, currency)
+	if len(unpaid) == 0 {
+		if len(all) > 0 {
+			return 0, ErrCurrencyMismatch
+		}
+		return 0, nil
+	}
+	var total money.Amount
+	for _, sh := range unpaid {
+		total += sh.Outstanding()
+	}
+	if amount > total {
+		return 0, ErrPayoutExceedsBalance
+	}
+
 	remaining := amount
-	var idsToPay []int
+	var allocations []domain.PayoutAllocation
 	for _, sh := range unpaid {
-		if sh.Amount <= remaining {
-			idsToPay = append(idsToPay, sh.ID)
-			remaining -= sh.Amount
-			if remaining <= 0 {
-				break
-			}
-		}
-	}
-	
-	for _, id := range idsToPay {
-		if err := s.Repo.(interface{ MarkShiftPaidByID(id int) error }).MarkShiftPaidByID(id); err != nil {
-			return err
-		}
-	}
-	
-	if remaining > 0 {
-		
-		paidSet := make(map[int]struct{}, len(idsToPay))
-		for _, id := range idsToPay {
-			paidSet[id] = struct{}{}
-		}
-
-		
-		var earliest *domain.DomainShift
-		for i := range unpaid {
-			sh := &unpaid[i]
-			if _, ok := paidSet[sh.ID]; ok {
-				continue
-			}
-			if earliest == nil || sh.Date.Before(earliest.Date) {
-				earliest = sh
-			}
-		}
-		if earliest != nil {
-			
-			
-			
-			if remaining >= earliest.Amount {
-				
-				if err := s.Repo.(interface{ MarkShiftPaidByID(id int) error }).MarkShiftPaidByID(earliest.ID); err != nil {
-					return err
-				}
-				remaining -= earliest.Amount
-			} else {
-				paidPortion := remaining
-				newUnpaidAmount := earliest.Amount - paidPortion
-				
-				if err := s.Repo.(interface{ UpdateShiftAmount(id int, amount float64) error }).UpdateShiftAmount(earliest.ID, newUnpaidAmount); err != nil {
-					return err
-				}
-				
-				paidShift := domain.DomainShift{
-					EmployeeID: employeeID,
-					Date:       earliest.Date,
-					Amount:     paidPortion,
-					Paid:       true,
-				}
-				if err := s.Repo.AddShift(paidShift); err != nil {
-					return err
-				}
-				remaining = 0
-			}
+		if remaining <= 0 {
+			break
 		}
+		part := sh.Outstanding()
+		if part > remaining {
+			part = remaining
+		}
+		allocations = append(allocations, domain.PayoutAllocation{ShiftID: sh.ID, Amount: part})
+		remaining -= part
 	}
-	return nil
+	status, err := s.newPayoutStatus(employeeID, recordedBy)
+	if err != nil {
+		return 0, err
+	}
+	return s.Payouts.CreatePayout(domain.Payout{
+		EmployeeID: employeeID,
+		Amount:     amount,
+		Date:       s.employeeToday(employeeID),
+		Note:       note,
+		RecordedBy: recordedBy,
+		Status:     status,
+		RecordedAt: time.Now(),
+		Currency:   currency,
+	}, allocations)
 }
 
-func (s *ShiftServiceImpl) CalculateSalary(employeeID int, from, to time.Time) (float64, error) {
+// CalculateSalary считает заработок за период по подтверждённым сменам,
+// отдельно по каждой валюте.
+func (s *ShiftServiceImpl) CalculateSalary(employeeID int, from, to time.Time) (money.Totals, error) {
 	shifts, err := s.Repo.GetShifts(employeeID, from, to)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	var total float64
+	total := money.Totals{}
 	for _, shift := range shifts {
-		total += shift.Amount
+		if shift.Approved() {
+			total.Add(shift.Currency, shift.Amount)
+		}
 	}
 	return total, nil
 }
 
-func (s *ShiftServiceImpl) MarkShiftsPaid(employeeID int, from, to time.Time) error {
-	return s.Repo.MarkShiftsPaid(employeeID, from, to)
+// CalculatePendingSalary — сумма смен, ожидающих подтверждения менеджера.
+func (s *ShiftServiceImpl) CalculatePendingSalary(employeeID int) (money.Totals, error) {
+	shifts, err := s.Repo.GetShifts(employeeID, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Now().AddDate(10, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+	total := money.Totals{}
+	for _, shift := range shifts {
+		if shift.Status == domain.ShiftPending {
+			total.Add(shift.Currency, shift.Amount)
+		}
+	}
+	return total, nil
+}
+
+// MarkShiftsPaid выплачивает весь остаток в валюте currency по сменам за
+// период одной выплатой.
+func (s *ShiftServiceImpl) MarkShiftsPaid(employeeID int, currency money.Currency, from, to time.Time, recordedBy int64) (int, error) {
+	all, err := s.unpaidShifts(employeeID, from, to)
+	if err != nil {
+		return 0, err
+	}
+	currency = currency.OrDefault()
+	unpaid := inCurrency(all, currency)
+	if len(unpaid) == 0 {
+		return 0, nil
+	}
+	var total money.Amount
+	allocations := make([]domain.PayoutAllocation, 0, len(unpaid))
+	for _, sh := range unpaid {
+		allocations = append(allocations, domain.PayoutAllocation{ShiftID: sh.ID, Amount: sh.Outstanding()})
+		total += sh.Outstanding()
+	}
+	status, err := s.newPayoutStatus(employeeID, recordedBy)
+	if err != nil {
+		return 0, err
+	}
+	return s.Payouts.CreatePayout(domain.Payout{
+		EmployeeID: employeeID,
+		Amount:     total,
+		Date:       s.employeeToday(employeeID),
+		RecordedBy: recordedBy,
+		Status:     status,
+		RecordedAt: time.Now(),
+		Currency:   currency,
+	}, allocations)
+}
+
+// newPayoutStatus — выплату, записанную менеджером, подтверждает сотрудник,
+// записанную сотрудником — менеджер. Если подтверждать некому, выплата
+// сразу подтверждена.
+func (s *ShiftServiceImpl) newPayoutStatus(employeeID int, recordedBy int64) (string, error) {
+	if s.Employees == nil {
+		return domain.PayoutConfirmed, nil
+	}
+	if recordedBy != int64(employeeID) {
+		return domain.PayoutUnconfirmed, nil
+	}
+	employees, err := s.Employees.GetAllEmployees()
+	if err != nil {
+		return "", err
+	}
+	for _, e := range employees {
+		if e.IsManager() && e.ID != employeeID {
+			return domain.PayoutUnconfirmed, nil
+		}
+	}
+	return domain.PayoutConfirmed, nil
+}
+
+// ConfirmPayout записывает ответ второй стороны: received == false —
+// выплата оспорена.
+func (s *ShiftServiceImpl) ConfirmPayout(payoutID int, received bool) (domain.Payout, error) {
+	p, err := s.Payouts.GetPayout(payoutID)
+	if err != nil {
+		return p, err
+	}
+	if p.RecordedByEmployee() {
+		if s.actor == int64(p.EmployeeID) {
+			return p, ErrNotPayoutCounterpart
+		}
+		if err := s.requireManager(); err != nil {
+			return p, err
+		}
+	} else if s.actor != int64(p.EmployeeID) {
+		return p, ErrNotPayoutCounterpart
+	}
+	if p.Status != domain.PayoutUnconfirmed {
+		return p, ErrPayoutReviewed
+	}
+	p.Status = domain.PayoutDisputed
+	if received {
+		p.Status = domain.PayoutConfirmed
+	}
+	return p, s.Payouts.UpdatePayoutStatus(p.ID, p.Status)
+}
+
+// UnconfirmedPayouts возвращает суммы выплат сотрудника, ещё не
+// подтверждённых второй стороной, и оспоренных.
+func (s *ShiftServiceImpl) UnconfirmedPayouts(employeeID int) (unconfirmed, disputed money.Totals, err error) {
+	payouts, err := s.Payouts.GetPayouts(employeeID, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Now().AddDate(10, 0, 0))
+	if err != nil {
+		return nil, nil, err
+	}
+	unconfirmed, disputed = money.Totals{}, money.Totals{}
+	for _, p := range payouts {
+		switch p.Status {
+		case domain.PayoutUnconfirmed:
+			unconfirmed.Add(p.Currency, p.Amount)
+		case domain.PayoutDisputed:
+			disputed.Add(p.Currency, p.Amount)
+		}
+	}
+	return unconfirmed, disputed, nil
+}
+
+// EscalatePayouts возвращает выплаты, не подтверждённые дольше delay,
+// отмечая, что о них напомнили: каждая выплата возвращается один раз.
+func (s *ShiftServiceImpl) EscalatePayouts(now time.Time, delay time.Duration) ([]domain.Payout, error) {
+	payouts, err := s.Payouts.GetUnconfirmedPayouts(now.Add(-delay))
+	if err != nil {
+		return nil, err
+	}
+	claimed := payouts[:0]
+	for _, p := range payouts {
+		ok, err := s.Payouts.ClaimEscalation(p.ID, now)
+		if err != nil {
+			return claimed, err
+		}
+		if ok {
+			claimed = append(claimed, p)
+		}
+	}
+	return claimed, nil
+}
+
+func (s *ShiftServiceImpl) GetPayout(payoutID int) (domain.Payout, error) {
+	return s.Payouts.GetPayout(payoutID)
 }
 
-func (s *ShiftServiceImpl) AddShift(employeeID int, date time.Time, amount float64) error {
+func (s *ShiftServiceImpl) AddShift(employeeID int, date time.Time, amount money.Amount) (int, error) {
+	status, err := s.newShiftStatus()
+	if err != nil {
+		return 0, err
+	}
 	shift := domain.DomainShift{
 		EmployeeID: employeeID,
 		Date:       date,
 		Amount:     amount,
 		Paid:       false,
+		Status:     status,
+		Currency:   s.EmployeeCurrency(employeeID),
 	}
 	return s.Repo.AddShift(shift)
 }
 
+// AddHourlyShift добавляет почасовую смену, сумма считается из длительности.
+func (s *ShiftServiceImpl) AddHourlyShift(employeeID int, date time.Time, hourly domain.HourlyShift) (int, money.Amount, error) {
+	status, err := s.newShiftStatus()
+	if err != nil {
+		return 0, 0, err
+	}
+	shift := domain.DomainShift{
+		EmployeeID: employeeID,
+		Date:       date,
+		Amount:     hourly.Amount(),
+		Hourly:     &hourly,
+		Status:     status,
+		Currency:   s.EmployeeCurrency(employeeID),
+	}
+	id, err := s.Repo.AddShift(shift)
+	return id, shift.Amount, err
+}
+
 func (s *ShiftServiceImpl) GetShifts(employeeID int, from, to time.Time) ([]domain.DomainShift, error) {
 	return s.Repo.GetShifts(employeeID, from, to)
 }
 
-func (s *ShiftServiceImpl) CalculateUnpaidSalary(employeeID int) (float64, error) {
-	from := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC) 
-	to := time.Now().AddDate(10, 0, 0)                  
-	shifts, err := s.Repo.GetShifts(employeeID, from, to)
+func (s *ShiftServiceImpl) GetLastShiftDate(employeeID int) (time.Time, error) {
+	return s.Repo.GetLastShiftDate(employeeID)
+}
+
+// GetEmployeeShift возвращает смену, только если она принадлежит сотруднику.
+func (s *ShiftServiceImpl) GetEmployeeShift(employeeID, shiftID int) (domain.DomainShift, error) {
+	sh, err := s.Repo.GetShiftByID(shiftID)
 	if err != nil {
+		return sh, err
+	}
+	if sh.EmployeeID != employeeID {
+		return domain.DomainShift{}, domain.ErrShiftNotFound
+	}
+	return sh, nil
+}
+
+// CanEditShift сообщает, может ли actor изменить или удалить смену sh:
+// по смене не должно быть выплат, а подтверждённую смену меняет только
+// менеджер. Если менеджеров в боте нет, смены подтверждаются сразу и
+// остаются в распоряжении сотрудника.
+func (s *ShiftServiceImpl) CanEditShift(sh domain.DomainShift) error {
+	if sh.Paid || sh.PaidAmount > 0 {
+		return ErrShiftPaid
+	}
+	if sh.Status != domain.ShiftApproved {
+		return nil
+	}
+	status, err := s.newShiftStatus()
+	if err != nil {
+		return err
+	}
+	if status == domain.ShiftPending {
+		return ErrShiftApproved
+	}
+	return nil
+}
+
+// editableShift — смена сотрудника, которую actor может изменить.
+func (s *ShiftServiceImpl) editableShift(employeeID, shiftID int) (domain.DomainShift, error) {
+	sh, err := s.GetEmployeeShift(employeeID, shiftID)
+	if err != nil {
+		return sh, err
+	}
+	return sh, s.CanEditShift(sh)
+}
+
+func (s *ShiftServiceImpl) UpdateShiftAmount(employeeID, shiftID int, amount money.Amount) error {
+	sh, err := s.editableShift(employeeID, shiftID)
+	if err != nil {
+		return err
+	}
+	if err := s.Repo.UpdateShiftAmount(shiftID, amount); err != nil {
+		return err
+	}
+	return s.reapprove(sh)
+}
+
+func (s *ShiftServiceImpl) UpdateShiftDate(employeeID, shiftID int, date time.Time) error {
+	sh, err := s.editableShift(employeeID, shiftID)
+	if err != nil {
+		return err
+	}
+	if err := s.Repo.UpdateShiftDate(shiftID, date); err != nil {
+		return err
+	}
+	return s.reapprove(sh)
+}
+
+// reapprove возвращает смену на подтверждение, если её изменил не менеджер.
+func (s *ShiftServiceImpl) reapprove(sh domain.DomainShift) error {
+	status, err := s.newShiftStatus()
+	if err != nil || status != domain.ShiftPending || sh.Status == domain.ShiftPending {
+		return err
+	}
+	return s.Repo.UpdateShiftStatus(sh.ID, status)
+}
+
+// newShiftStatus — статус смены, которую добавляет actor: менеджер (или
+// любой, пока менеджеров нет) добавляет сразу подтверждённую смену.
+func (s *ShiftServiceImpl) newShiftStatus() (string, error) {
+	if s.Employees == nil {
+		return domain.ShiftApproved, nil
+	}
+	employees, err := s.Employees.GetAllEmployees()
+	if err != nil {
+		return "", err
+	}
+	hasManagers := false
+	for _, e := range employees {
+		if !e.IsManager() {
+			continue
+		}
+		if int64(e.ID) == s.actor {
+			return domain.ShiftApproved, nil
+		}
+		hasManagers = true
+	}
+	if hasManagers {
+		return domain.ShiftPending, nil
+	}
+	return domain.ShiftApproved, nil
+}
+
+// ReviewShift подтверждает или отклоняет смену, ожидающую подтверждения.
+// Решение принимает только менеджер.
+func (s *ShiftServiceImpl) ReviewShift(shiftID int, approve bool) (domain.DomainShift, error) {
+	if err := s.requireManager(); err != nil {
+		return domain.DomainShift{}, err
+	}
+	sh, err := s.Repo.GetShiftByID(shiftID)
+	if err != nil {
+		return sh, err
+	}
+	if sh.Status != domain.ShiftPending {
+		return sh, ErrShiftReviewed
+	}
+	sh.Status = domain.ShiftRejected
+	if approve {
+		sh.Status = domain.ShiftApproved
+	}
+	return sh, s.Repo.UpdateShiftStatus(sh.ID, sh.Status)
+}
+
+// ApprovePending подтверждает все ожидающие смены сотрудника, а вместе
+// с ними — выплаты импорта, которые покрывают только эти смены: иначе они
+// ждали бы подтверждения, хотя смены уже проверил менеджер.
+func (s *ShiftServiceImpl) ApprovePending(employeeID int) (int, error) {
+	if err := s.requireManager(); err != nil {
 		return 0, err
 	}
-	var total float64
-	for _, shift := range shifts {
-		if !shift.Paid {
-			total += shift.Amount
+	shifts, err := s.Repo.GetShifts(employeeID, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Now().AddDate(10, 0, 0))
+	if err != nil {
+		return 0, err
+	}
+	var ids []int
+	pending := make(map[int]bool)
+	for _, sh := range shifts {
+		if sh.Status == domain.ShiftPending {
+			ids = append(ids, sh.ID)
+			pending[sh.ID] = true
 		}
 	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	payoutIDs, err := s.importPayouts(employeeID, pending)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.Repo.ApproveShifts(ids, payoutIDs); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// importPayouts возвращает неподтверждённые выплаты импорта, все смены
+// которых входят в shifts.
+func (s *ShiftServiceImpl) importPayouts(employeeID int, shifts map[int]bool) ([]int, error) {
+	payouts, err := s.Payouts.GetPayouts(employeeID, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Now().AddDate(10, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for _, p := range payouts {
+		if p.Note != domain.PayoutNoteImport || p.Status != domain.PayoutUnconfirmed {
+			continue
+		}
+		allocations, err := s.Payouts.GetAllocations(p.ID)
+		if err != nil {
+			return nil, err
+		}
+		covered := len(allocations) > 0
+		for _, a := range allocations {
+			covered = covered && shifts[a.ShiftID]
+		}
+		if covered {
+			ids = append(ids, p.ID)
+		}
+	}
+	return ids, nil
+}
+
+// approvedOnly оставляет подтверждённые смены — только они идут в расчёт.
+func approvedOnly(shifts []domain.DomainShift) []domain.DomainShift {
+	out := shifts[:0:0]
+	for _, sh := range shifts {
+		if sh.Approved() {
+			out = append(out, sh)
+		}
+	}
+	return out
+}
+
+func (s *ShiftServiceImpl) requireManager() error {
+	if s.Employees == nil {
+		return nil
+	}
+	e, err := s.Employees.GetEmployeeByID(int(s.actor))
+	if err != nil || !e.IsManager() {
+		return ErrNotManager
+	}
+	return nil
+}
+
+func (s *ShiftServiceImpl) DeleteShift(employeeID, shiftID int) error {
+	if _, err := s.editableShift(employeeID, shiftID); err != nil {
+		return err
+	}
+	return s.Repo.DeleteShift(shiftID)
+}
+
+func (s *ShiftServiceImpl) GetShiftPayouts(shiftID int) ([]domain.Payout, error) {
+	return s.Payouts.GetShiftPayouts(shiftID)
+}
+
+// ReversalOf сообщает, что ReversePayout сделает с выплатой p от имени
+// actor. Удалить выплату может записавший её или менеджер, пока вторая
+// сторона её не подтвердила; сотрудник, которому выплату записал
+// менеджер, может её только оспорить. Подтверждённую выплату не меняет
+// никто, кроме случая, когда подтверждать её было некому.
+func (s *ShiftServiceImpl) ReversalOf(p domain.Payout) (string, error) {
+	if s.actor != p.RecordedBy && s.requireManager() != nil {
+		switch p.Status {
+		case domain.PayoutConfirmed:
+			return "", ErrPayoutConfirmed
+		case domain.PayoutDisputed:
+			return "", ErrPayoutReviewed
+		}
+		return ReversalDispute, nil
+	}
+	if p.Status == domain.PayoutConfirmed {
+		// без второй стороны выплата подтверждается сразу при записи
+		status, err := s.newPayoutStatus(p.EmployeeID, p.RecordedBy)
+		if err != nil {
+			return "", err
+		}
+		if status != domain.PayoutConfirmed {
+			return "", ErrPayoutConfirmed
+		}
+	}
+	return ReversalDelete, nil
+}
+
+// ReversePayout отменяет выплату сотрудника целиком или, если удалить её
+// actor не может, оспаривает (см. ReversalOf). Возвращает выплату с новым
+// статусом.
+func (s *ShiftServiceImpl) ReversePayout(employeeID, payoutID int) (domain.Payout, error) {
+	p, err := s.Payouts.GetPayout(payoutID)
+	if err != nil {
+		return p, err
+	}
+	if p.EmployeeID != employeeID {
+		return domain.Payout{}, domain.ErrPayoutNotFound
+	}
+	reversal, err := s.ReversalOf(p)
+	if err != nil {
+		return p, err
+	}
+	if reversal == ReversalDispute {
+		p.Status = domain.PayoutDisputed
+		return p, s.Payouts.UpdatePayoutStatus(p.ID, p.Status)
+	}
+	return p, s.Payouts.DeletePayout(payoutID)
+}
+
+func (s *ShiftServiceImpl) GetPayouts(employeeID int, from, to time.Time) ([]domain.Payout, error) {
+	return s.Payouts.GetPayouts(employeeID, from, to)
+}
+
+// CalculateUnpaidSalary возвращает невыплаченный остаток по валютам.
+func (s *ShiftServiceImpl) CalculateUnpaidSalary(employeeID int) (money.Totals, error) {
+	from := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Now().AddDate(10, 0, 0)
+	unpaid, err := s.unpaidShifts(employeeID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	total := money.Totals{}
+	for _, shift := range unpaid {
+		total.Add(shift.Currency, shift.Outstanding())
+	}
 	return total, nil
 }
+
+func inCurrency(shifts []domain.DomainShift, currency money.Currency) []domain.DomainShift {
+	out := shifts[:0:0]
+	for _, sh := range shifts {
+		if sh.Currency.OrDefault() == currency {
+			out = append(out, sh)
+		}
+	}
+	return out
+}
+
+// EmployeeCurrency — валюта оплаты сотрудника, в ней записываются новые смены.
+func (s *ShiftServiceImpl) EmployeeCurrency(employeeID int) money.Currency {
+	if s.Employees == nil {
+		return money.DefaultCurrency
+	}
+	e, err := s.Employees.GetEmployeeByID(employeeID)
+	if err != nil {
+		return money.DefaultCurrency
+	}
+	return e.Currency.OrDefault()
+}
+
+// employeeToday — сегодняшняя дата в поясе сотрудника: ею датируются
+// выплаты.
+func (s *ShiftServiceImpl) employeeToday(employeeID int) time.Time {
+	if s.Employees != nil {
+		if e, err := s.Employees.GetEmployeeByID(employeeID); err == nil {
+			return e.Today()
+		}
+	}
+	return domain.DateOf(time.Now())
+}
+
+// unpaidShifts возвращает смены с ненулевым остатком, от ранних к поздним.
+func (s *ShiftServiceImpl) unpaidShifts(employeeID int, from, to time.Time) ([]domain.DomainShift, error) {
+	shifts, err := s.Repo.GetShifts(employeeID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	unpaid := make([]domain.DomainShift, 0, len(shifts))
+	for _, sh := range shifts {
+		if sh.Outstanding() > 0 {
+			unpaid = append(unpaid, sh)
+		}
+	}
+	sort.SliceStable(unpaid, func(i, j int) bool {
+		if unpaid[i].Date.Equal(unpaid[j].Date) {
+			return unpaid[i].ID < unpaid[j].ID
+		}
+		return unpaid[i].Date.Before(unpaid[j].Date)
+	})
+	return unpaid, nil
+}
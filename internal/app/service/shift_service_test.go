@@ -0,0 +1,288 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"salary-bot/internal/domain"
+)
+
+// payoutRepo — выплаты в памяти.
+type payoutRepo struct {
+	domain.PayoutRepo
+	payouts     map[int]domain.Payout
+	allocations map[int][]domain.PayoutAllocation
+}
+
+func (r *payoutRepo) add(p domain.Payout, allocations []domain.PayoutAllocation) int {
+	if r.payouts == nil {
+		r.payouts, r.allocations = map[int]domain.Payout{}, map[int][]domain.PayoutAllocation{}
+	}
+	p.ID = len(r.payouts) + 1
+	r.payouts[p.ID] = p
+	r.allocations[p.ID] = allocations
+	return p.ID
+}
+
+func (r *payoutRepo) GetPayouts(employeeID int, from, to time.Time) ([]domain.Payout, error) {
+	var out []domain.Payout
+	for id := 1; id <= len(r.payouts); id++ {
+		if p, ok := r.payouts[id]; ok && p.EmployeeID == employeeID {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (r *payoutRepo) GetAllocations(payoutID int) ([]domain.PayoutAllocation, error) {
+	return r.allocations[payoutID], nil
+}
+
+func (r *payoutRepo) GetPayout(id int) (domain.Payout, error) {
+	p, ok := r.payouts[id]
+	if !ok {
+		return p, domain.ErrPayoutNotFound
+	}
+	return p, nil
+}
+
+func (r *payoutRepo) UpdatePayoutStatus(id int, status string) error {
+	p, ok := r.payouts[id]
+	if !ok {
+		return domain.ErrPayoutNotFound
+	}
+	p.Status = status
+	r.payouts[id] = p
+	return nil
+}
+
+func (r *payoutRepo) DeletePayout(id int) error {
+	delete(r.payouts, id)
+	return nil
+}
+
+func (r *payoutRepo) DeleteByEmployee(employeeID int) error {
+	for id, p := range r.payouts {
+		if p.EmployeeID == employeeID {
+			delete(r.payouts, id)
+		}
+	}
+	return nil
+}
+
+func (r *importRepo) GetShiftByID(id int) (domain.DomainShift, error) {
+	for _, sh := range r.shifts {
+		if sh.ID == id {
+			return sh, nil
+		}
+	}
+	return domain.DomainShift{}, domain.ErrShiftNotFound
+}
+
+func (r *importRepo) DeleteShift(id int) error {
+	return r.deleteWhere(func(sh domain.DomainShift) bool { return sh.ID == id })
+}
+
+func (r *importRepo) DeleteByEmployee(employeeID int) error {
+	return r.deleteWhere(func(sh domain.DomainShift) bool { return sh.EmployeeID == employeeID })
+}
+
+// deleteWhere удаляет смены, для которых match возвращает true.
+func (r *importRepo) deleteWhere(match func(domain.DomainShift) bool) error {
+	kept := r.shifts[:0]
+	for _, sh := range r.shifts {
+		if !match(sh) {
+			kept = append(kept, sh)
+		}
+	}
+	r.shifts = kept
+	return nil
+}
+
+// employeeRepo — сотрудники в памяти.
+type employeeRepo struct {
+	domain.EmployeeRepo
+	employees []domain.Employee
+}
+
+func (r *employeeRepo) GetAllEmployees() ([]domain.Employee, error) {
+	return r.employees, nil
+}
+
+func (r *employeeRepo) GetEmployeeByID(id int) (domain.Employee, error) {
+	for _, e := range r.employees {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return domain.Employee{}, domain.ErrEmployeeNotFound
+}
+
+func TestReversePayout(t *testing.T) {
+	const (
+		manager  = 1
+		employee = 2
+	)
+	staff := []domain.Employee{{ID: manager, Role: domain.RoleManager}, {ID: employee, Role: domain.RoleEmployee}}
+	tests := []struct {
+		name      string
+		employees []domain.Employee
+		actor     int64
+		payout    domain.Payout
+		want      error
+		// wantStatus — статус после отмены, "" — выплата удалена
+		wantStatus string
+	}{
+		{
+			name:      "сотрудник не может отменить подтверждённую выплату менеджера",
+			employees: staff, actor: employee,
+			payout:     domain.Payout{RecordedBy: manager, Status: domain.PayoutConfirmed},
+			want:       ErrPayoutConfirmed,
+			wantStatus: domain.PayoutConfirmed,
+		},
+		{
+			name:      "неподтверждённую выплату менеджера сотрудник оспаривает",
+			employees: staff, actor: employee,
+			payout:     domain.Payout{RecordedBy: manager, Status: domain.PayoutUnconfirmed},
+			wantStatus: domain.PayoutDisputed,
+		},
+		{
+			name:      "оспоренную выплату оспорить ещё раз нельзя",
+			employees: staff, actor: employee,
+			payout:     domain.Payout{RecordedBy: manager, Status: domain.PayoutDisputed},
+			want:       ErrPayoutReviewed,
+			wantStatus: domain.PayoutDisputed,
+		},
+		{
+			name:      "сотрудник отменяет свою неподтверждённую выплату",
+			employees: staff, actor: employee,
+			payout: domain.Payout{RecordedBy: employee, Status: domain.PayoutUnconfirmed},
+		},
+		{
+			name:      "подтверждённую менеджером выплату сотрудник не отменяет",
+			employees: staff, actor: employee,
+			payout:     domain.Payout{RecordedBy: employee, Status: domain.PayoutConfirmed},
+			want:       ErrPayoutConfirmed,
+			wantStatus: domain.PayoutConfirmed,
+		},
+		{
+			name:      "подтверждённую сотрудником выплату менеджер не отменяет",
+			employees: staff, actor: manager,
+			payout:     domain.Payout{RecordedBy: manager, Status: domain.PayoutConfirmed},
+			want:       ErrPayoutConfirmed,
+			wantStatus: domain.PayoutConfirmed,
+		},
+		{
+			name:      "менеджер отменяет неподтверждённую выплату сотрудника",
+			employees: staff, actor: manager,
+			payout: domain.Payout{RecordedBy: employee, Status: domain.PayoutUnconfirmed},
+		},
+		{
+			name:      "без менеджеров выплата подтверждена сразу и отменяется",
+			employees: staff[1:], actor: employee,
+			payout: domain.Payout{RecordedBy: employee, Status: domain.PayoutConfirmed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.payout
+			p.ID, p.EmployeeID = 10, employee
+			repo := &payoutRepo{payouts: map[int]domain.Payout{p.ID: p}}
+			s := (&ShiftServiceImpl{Payouts: repo, Employees: &employeeRepo{employees: tt.employees}}).As(tt.actor)
+
+			_, err := s.ReversePayout(employee, p.ID)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			got, ok := repo.payouts[p.ID]
+			if tt.wantStatus == "" && ok {
+				t.Errorf("payout kept with status %q, want deleted", got.Status)
+			}
+			if tt.wantStatus != "" && (!ok || got.Status != tt.wantStatus) {
+				t.Errorf("payout = %+v (exists %v), want status %q", got, ok, tt.wantStatus)
+			}
+		})
+	}
+
+	s := (&ShiftServiceImpl{Payouts: &payoutRepo{payouts: map[int]domain.Payout{
+		10: {ID: 10, EmployeeID: employee, RecordedBy: employee, Status: domain.PayoutUnconfirmed},
+	}}}).As(3)
+	if _, err := s.ReversePayout(3, 10); !errors.Is(err, domain.ErrPayoutNotFound) {
+		t.Errorf("someone else's payout: err = %v", err)
+	}
+}
+
+func TestDeleteShift(t *testing.T) {
+	const (
+		manager  = 1
+		employee = 2
+	)
+	staff := []domain.Employee{{ID: manager, Role: domain.RoleManager}, {ID: employee, Role: domain.RoleEmployee}}
+	tests := []struct {
+		name      string
+		employees []domain.Employee
+		actor     int64
+		shift     domain.DomainShift
+		want      error
+	}{
+		{"сотрудник удаляет смену на подтверждении", staff, employee, domain.DomainShift{Status: domain.ShiftPending}, nil},
+		{"сотрудник удаляет отклонённую смену", staff, employee, domain.DomainShift{Status: domain.ShiftRejected}, nil},
+		{"подтверждённую смену сотрудник не удаляет", staff, employee, domain.DomainShift{Status: domain.ShiftApproved}, ErrShiftApproved},
+		{"подтверждённую смену удаляет менеджер", staff, manager, domain.DomainShift{Status: domain.ShiftApproved}, nil},
+		{"без менеджеров смены сотрудника в его распоряжении", staff[1:], employee, domain.DomainShift{Status: domain.ShiftApproved}, nil},
+		{"оплаченную смену не удаляет никто", staff, manager, domain.DomainShift{Status: domain.ShiftApproved, PaidAmount: 100}, ErrShiftPaid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sh := tt.shift
+			sh.ID, sh.EmployeeID, sh.Amount = 1, employee, 1000
+			repo := &importRepo{shifts: []domain.DomainShift{sh}}
+			s := (&ShiftServiceImpl{Repo: repo, Employees: &employeeRepo{employees: tt.employees}}).As(tt.actor)
+
+			if err := s.DeleteShift(employee, sh.ID); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if deleted := len(repo.shifts) == 0; deleted != (tt.want == nil) {
+				t.Errorf("deleted = %v", deleted)
+			}
+		})
+	}
+}
+
+func TestResetEmployeeData(t *testing.T) {
+	const (
+		manager  = 1
+		employee = 2
+	)
+	staff := []domain.Employee{{ID: manager, Role: domain.RoleManager}, {ID: employee, Role: domain.RoleEmployee}}
+	tests := []struct {
+		name      string
+		employees []domain.Employee
+		shift     string
+		payout    string
+		want      error
+	}{
+		{"только неподтверждённые данные", staff, domain.ShiftPending, domain.PayoutUnconfirmed, nil},
+		{"подтверждённая смена", staff, domain.ShiftApproved, domain.PayoutUnconfirmed, ErrResetLocked},
+		{"подтверждённая выплата", staff, domain.ShiftRejected, domain.PayoutConfirmed, ErrResetLocked},
+		{"без менеджеров сбрасывается всё", staff[1:], domain.ShiftApproved, domain.PayoutConfirmed, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shifts := &importRepo{shifts: []domain.DomainShift{
+				{ID: 1, EmployeeID: employee, Date: date("2024-03-01"), Amount: 1000, Status: tt.shift},
+			}}
+			payouts := &payoutRepo{}
+			payouts.add(domain.Payout{EmployeeID: employee, Amount: 500, RecordedBy: manager, Status: tt.payout}, nil)
+			s := (&ShiftServiceImpl{Repo: shifts, Payouts: payouts, Employees: &employeeRepo{employees: tt.employees}}).As(employee)
+
+			if err := s.ResetEmployeeData(employee); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if reset := len(shifts.shifts) == 0 && len(payouts.payouts) == 0; reset != (tt.want == nil) {
+				t.Errorf("shifts %+v, payouts %+v left", shifts.shifts, payouts.payouts)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"salary-bot/internal/domain"
+)
+
+// DefaultUndoWindow — сколько времени после действия его можно отменить.
+const DefaultUndoWindow = 10 * time.Minute
+
+var (
+	ErrNothingToUndo = errors.New("нечего отменять")
+	ErrUndoExpired   = errors.New("время на отмену истекло")
+	ErrUndoNotLatest = errors.New("отменить можно только последнее действие")
+)
+
+// UndoService отменяет последнее добавление смены или выплату пользователя.
+type UndoService struct {
+	Repo   domain.UndoRepo
+	Shifts *ShiftServiceImpl
+	Window time.Duration
+}
+
+func NewUndoService(repo domain.UndoRepo, shifts *ShiftServiceImpl, window time.Duration) *UndoService {
+	if window <= 0 {
+		window = DefaultUndoWindow
+	}
+	return &UndoService{Repo: repo, Shifts: shifts, Window: window}
+}
+
+// Record запоминает действие и возвращает его ID для кнопки «Отменить».
+func (s *UndoService) Record(actorID int64, employeeID int, kind string, refID int) (int, error) {
+	return s.Repo.AddUndoAction(domain.UndoAction{
+		ActorID:    actorID,
+		EmployeeID: employeeID,
+		Kind:       kind,
+		RefID:      refID,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// UndoLast отменяет последнее действие пользователя.
+func (s *UndoService) UndoLast(actorID int64) (domain.UndoAction, error) {
+	return s.undo(actorID, 0)
+}
+
+// Undo отменяет действие actionID, если оно всё ещё последнее у пользователя.
+func (s *UndoService) Undo(actorID int64, actionID int) (domain.UndoAction, error) {
+	return s.undo(actorID, actionID)
+}
+
+func (s *UndoService) undo(actorID int64, actionID int) (domain.UndoAction, error) {
+	a, ok, err := s.Repo.GetLastUndoAction(actorID)
+	if err != nil {
+		return a, err
+	}
+	if !ok {
+		return a, ErrNothingToUndo
+	}
+	if actionID != 0 && a.ID != actionID {
+		return a, ErrUndoNotLatest
+	}
+	if time.Since(a.CreatedAt) > s.Window {
+		_ = s.Repo.DeleteUndoAction(a.ID)
+		return a, ErrUndoExpired
+	}
+	shifts := s.Shifts.As(actorID)
+	switch a.Kind {
+	case domain.UndoShiftAdded:
+		err = shifts.DeleteShift(a.EmployeeID, a.RefID)
+	case domain.UndoPayout:
+		_, err = shifts.ReversePayout(a.EmployeeID, a.RefID)
+	}
+	// смену или выплату уже удалили вручную — отменять нечего
+	if errors.Is(err, domain.ErrShiftNotFound) || errors.Is(err, domain.ErrPayoutNotFound) {
+		_ = s.Repo.DeleteUndoAction(a.ID)
+		return a, ErrNothingToUndo
+	}
+	if err != nil {
+		return a, err
+	}
+	return a, s.Repo.DeleteUndoAction(a.ID)
+}
+
+// RunCleanup периодически удаляет действия старше окна отмены: отменить
+// их уже нельзя. Блокирует вызывающего.
+func (s *UndoService) RunCleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		n, err := s.Repo.DeleteUndoActionsBefore(now.Add(-s.Window))
+		if err != nil {
+			log.Printf("[undo] cleanup: %v", err)
+			continue
+		}
+		if n > 0 {
+			log.Printf("[undo] cleanup removed %d expired actions", n)
+		}
+	}
+}
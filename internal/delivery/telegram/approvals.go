@@ -0,0 +1,122 @@
+package telegram
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"salary-bot/internal/app/service"
+	"salary-bot/internal/delivery/telegram/middleware"
+	"salary-bot/internal/delivery/telegram/router"
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/i18n"
+
+	"gopkg.in/telebot.v3"
+)
+
+// requestApproval отправляет менеджерам смену, ожидающую подтверждения,
+// и возвращает пометку для ответа сотруднику. Для подтверждённой смены
+// возвращается пустая строка.
+func (h *Handler) requestApproval(c telebot.Context, empID, shiftID int) string {
+	sh, err := h.Shifts.GetEmployeeShift(empID, shiftID)
+	if err != nil || sh.Status != domain.ShiftPending {
+		return ""
+	}
+	name := h.employeeName(empID)
+	id := strconv.Itoa(sh.ID)
+	h.sendManagers(c.Sender().ID, func(l i18n.Lang) (string, *telebot.ReplyMarkup) {
+		m := &telebot.ReplyMarkup{}
+		m.Inline(m.Row(m.Data(l.T("approval.btn_approve"), "shift_approve", id), m.Data(l.T("approval.btn_reject"), "shift_reject", id)))
+		return l.T("approval.request", name, l.Date(sh.Date), l.Money(sh.Currency, sh.Amount)), m
+	})
+	return "\n" + h.lang(c).T("approval.pending")
+}
+
+// requestImportApproval — то же для импорта: одно сообщение на все смены.
+func (h *Handler) requestImportApproval(c telebot.Context, empID, n int) string {
+	pending, err := h.Shifts.CalculatePendingSalary(empID)
+	if err != nil || pending.IsZero() {
+		return ""
+	}
+	name := h.employeeName(empID)
+	h.sendManagers(c.Sender().ID, func(l i18n.Lang) (string, *telebot.ReplyMarkup) {
+		m := &telebot.ReplyMarkup{}
+		m.Inline(m.Row(m.Data(l.T("approval.btn_approve_all"), "shift_approve_all", strconv.Itoa(empID))))
+		return l.T("approval.import_request", name, l.N("shifts", n), l.Totals(pending)), m
+	})
+	return "\n" + h.lang(c).T("approval.import_pending")
+}
+
+func (h *Handler) employeeName(id int) string {
+	e, err := h.Employees.GetEmployeeByID(id)
+	if err != nil {
+		return "id " + strconv.Itoa(id)
+	}
+	return employeeTitle(e)
+}
+
+// sendManagers рассылает сообщение всем менеджерам, кроме skip; msg
+// собирает текст и кнопки на языке менеджера.
+func (h *Handler) sendManagers(skip int64, msg func(l i18n.Lang) (string, *telebot.ReplyMarkup)) {
+	employees, err := h.Employees.GetAllEmployees()
+	if err != nil {
+		log.Printf("[managers] employees: %v", err)
+		return
+	}
+	for _, e := range employees {
+		if !e.IsManager() || e.ChatID == 0 || int64(e.ID) == skip {
+			continue
+		}
+		text, markup := msg(h.langOf(e))
+		if _, err := h.Bot.Send(telebot.ChatID(e.ChatID), text, markup); err != nil {
+			log.Printf("[managers] send manager=%d: %v", e.ID, err)
+		}
+	}
+}
+
+func (h *Handler) registerApprovals(r *router.CallbackRouter) {
+	review := func(approve bool) router.HandlerFunc {
+		return func(c telebot.Context, payload string) error {
+			id, err := strconv.Atoi(payload)
+			if err != nil {
+				return nil
+			}
+			l := h.lang(c)
+			sh, err := h.Shifts.As(c.Sender().ID).ReviewShift(id, approve)
+			if errors.Is(err, service.ErrShiftReviewed) || errors.Is(err, domain.ErrShiftNotFound) {
+				return middleware.EditOrSend(c, l.T("approval.reviewed"), nil)
+			}
+			if err != nil {
+				return c.Send(l.T("err.generic", errText(l, err)))
+			}
+			decision := func(l i18n.Lang) string {
+				if approve {
+					return l.T("approval.approved")
+				}
+				return l.T("approval.rejected")
+			}
+			h.notifyTarget(c, sh.EmployeeID, func(l i18n.Lang) string {
+				return l.T("notify.shift_reviewed", l.Date(sh.Date), l.Money(sh.Currency, sh.Amount), decision(l))
+			})
+			return middleware.EditOrSend(c, "👤 "+h.employeeName(sh.EmployeeID)+"\n"+
+				l.T("approval.shift", l.Date(sh.Date), l.Money(sh.Currency, sh.Amount), decision(l)), nil)
+		}
+	}
+	r.Register("shift_approve", review(true))
+	r.Register("shift_reject", review(false))
+	r.Register("shift_approve_all", func(c telebot.Context, payload string) error {
+		empID, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil
+		}
+		l := h.lang(c)
+		n, err := h.Shifts.As(c.Sender().ID).ApprovePending(empID)
+		if err != nil {
+			return c.Send(l.T("err.generic", errText(l, err)))
+		}
+		if n > 0 {
+			h.notifyTarget(c, empID, func(l i18n.Lang) string { return l.T("notify.approved_n", n) })
+		}
+		return middleware.EditOrSend(c, "👤 "+h.employeeName(empID)+"\n"+l.T("approval.approved_n", n), nil)
+	})
+}
@@ -0,0 +1,28 @@
+package telegram
+
+import (
+	"salary-bot/pkg/money"
+
+	"gopkg.in/telebot.v3"
+)
+
+// /currency USD — валюта, в которой записываются новые смены. Менеджер
+// меняет валюту выбранного сотрудника.
+func (h *Handler) handleCurrency(c telebot.Context) error {
+	l := h.lang(c)
+	if _, err := h.registerSender(c); err != nil {
+		return c.Send(l.T("err.data", errText(l, err)))
+	}
+	e, _ := h.target(c)
+	if len(c.Args()) == 0 {
+		return c.Send(h.targetHeader(c) + l.T("currency.current", string(e.Currency.OrDefault()), currencyList()))
+	}
+	currency, err := money.ParseCurrency(c.Args()[0])
+	if err != nil {
+		return c.Send(l.T("currency.unknown", currencyList()))
+	}
+	if _, err := h.Employees.As(c.Sender().ID).SetCurrency(e.ID, currency); err != nil {
+		return c.Send(l.T("err.save", errText(l, err)))
+	}
+	return c.Send(h.targetHeader(c) + l.T("currency.set", string(currency)))
+}
@@ -0,0 +1,160 @@
+package telegram
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"salary-bot/internal/delivery/telegram/middleware"
+	"salary-bot/internal/delivery/telegram/router"
+	"salary-bot/internal/domain"
+
+	"gopkg.in/telebot.v3"
+)
+
+// promptWindow — сколько после назначенного времени вопрос ещё можно
+// отправить (например, если бот был перезапущен).
+const promptWindow = time.Hour
+
+// /remind 20:30 — ежедневный вопрос «работали сегодня?», /remind off — выключить.
+func (h *Handler) handleRemind(c telebot.Context) error {
+	me, err := h.registerSender(c)
+	l := h.lang(c)
+	if err != nil {
+		return c.Send(l.T("err.data", errText(l, err)))
+	}
+	args := c.Args()
+	if len(args) == 0 {
+		if me.PromptTime == "" {
+			return c.Send(l.T("remind.off"))
+		}
+		return c.Send(l.T("remind.current", me.PromptTime))
+	}
+	clock := ""
+	if !strings.EqualFold(args[0], "off") {
+		d, err := domain.ParseClock(args[0])
+		if err != nil {
+			return c.Send(l.T("remind.invalid"))
+		}
+		clock = domain.FormatClock(d)
+	}
+	if _, err := h.Employees.As(c.Sender().ID).SetPromptTime(me.ID, clock); err != nil {
+		return c.Send(l.T("err.save", errText(l, err)))
+	}
+	if clock == "" {
+		return c.Send(l.T("remind.disabled"))
+	}
+	return c.Send(l.T("remind.set", clock))
+}
+
+// RunEveningPrompts раз в interval отправляет вопрос о смене тем, у кого
+// подошло время. Блокирует вызывающего.
+func (h *Handler) RunEveningPrompts(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		h.sendEveningPrompts(now)
+	}
+}
+
+func (h *Handler) sendEveningPrompts(now time.Time) {
+	employees, err := h.Employees.GetAllEmployees()
+	if err != nil {
+		log.Printf("[prompt] employees: %v", err)
+		return
+	}
+	for _, e := range employees {
+		if e.PromptTime == "" || e.ChatID == 0 {
+			continue
+		}
+		// время вопроса и «сегодня» — в поясе сотрудника
+		local := now.In(e.Location())
+		today := domain.DateOf(local)
+		sinceMidnight := local.Sub(time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location()))
+		at, err := domain.ParseClock(e.PromptTime)
+		if err != nil || sinceMidnight < at || sinceMidnight >= at+promptWindow {
+			continue
+		}
+		shifts, err := h.Shifts.GetShifts(e.ID, today, today)
+		if err != nil {
+			log.Printf("[prompt] shifts employee=%d: %v", e.ID, err)
+			continue
+		}
+		if len(shifts) > 0 {
+			continue
+		}
+		if ok, err := h.Employees.ClaimPrompt(e.ID, today); err != nil || !ok {
+			if err != nil {
+				log.Printf("[prompt] claim employee=%d: %v", e.ID, err)
+			}
+			continue
+		}
+		l := h.langOf(e)
+		day := today.Format("2006-01-02")
+		markup := &telebot.ReplyMarkup{}
+		btnRate := markup.Data(l.T("evening.btn_rate"), "evening_rate", day)
+		btnOther := markup.Data(l.T("evening.btn_other"), "evening_other", day)
+		btnNo := markup.Data(l.T("evening.btn_no"), "evening_no", day)
+		markup.Inline(markup.Row(btnRate, btnOther), markup.Row(btnNo))
+		if _, err := h.Bot.Send(telebot.ChatID(e.ChatID), l.T("evening.ask"), markup); err != nil {
+			log.Printf("[prompt] send employee=%d: %v", e.ID, err)
+			// вопрос не дошёл — пока окно открыто, следующий тик спросит снова
+			if err := h.Employees.ReleasePrompt(e.ID, today); err != nil {
+				log.Printf("[prompt] release employee=%d: %v", e.ID, err)
+			}
+		}
+	}
+}
+
+func (h *Handler) registerEveningPrompt(r *router.CallbackRouter) {
+	// Вопрос всегда о своей смене: менеджер, работавший с данными
+	// сотрудника, возвращается к своим.
+	parseDay := func(c telebot.Context, payload string) (time.Time, bool) {
+		d, err := time.Parse("2006-01-02", payload)
+		if err != nil {
+			return d, false
+		}
+		if _, onBehalf := h.target(c); onBehalf {
+			if err := h.Employees.StopActing(c.Sender().ID); err != nil {
+				log.Printf("[prompt] stop acting sender=%d: %v", c.Sender().ID, err)
+				return d, false
+			}
+		}
+		return d, true
+	}
+	r.Register("evening_rate", func(c telebot.Context, payload string) error {
+		date, ok := parseDay(c, payload)
+		if !ok {
+			return nil
+		}
+		if me, err := h.Employees.GetEmployeeByID(int(c.Sender().ID)); err == nil && me.IsHourly() {
+			return h.askShiftTimes(c, date)
+		}
+		l := h.lang(c)
+		rate, ok := h.shiftRate(c, date)
+		if !ok {
+			_ = middleware.EditOrSend(c, l.T("evening.no_rate"), nil)
+			return h.askShiftAmount(c, date)
+		}
+		h.cancelFlow(c.Chat().ID)
+		empID := int(c.Sender().ID)
+		shiftID, err := h.Shifts.As(c.Sender().ID).AddShift(empID, date, rate)
+		if err != nil {
+			return c.Send(l.T("shift.add_failed", errText(l, err)))
+		}
+		return middleware.EditOrSend(c, l.T("evening.added", l.Date(date), h.formatFor(l, empID, rate))+h.requestApproval(c, empID, shiftID),
+			h.recordUndo(c, empID, domain.UndoShiftAdded, shiftID))
+	})
+	r.Register("evening_other", func(c telebot.Context, payload string) error {
+		date, ok := parseDay(c, payload)
+		if !ok {
+			return nil
+		}
+		l := h.lang(c)
+		_ = middleware.EditOrSend(c, l.T("evening.shift_for", l.Date(date)), nil)
+		return h.askShiftAmount(c, date)
+	})
+	r.Register("evening_no", func(c telebot.Context, payload string) error {
+		return middleware.EditOrSend(c, h.lang(c).T("evening.rest"), nil)
+	})
+}
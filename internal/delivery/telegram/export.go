@@ -0,0 +1,112 @@
+package telegram
+
+import (
+	"bytes"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"salary-bot/internal/app/service"
+	"salary-bot/internal/delivery/telegram/keyboards"
+	"salary-bot/internal/delivery/telegram/middleware"
+	"salary-bot/internal/delivery/telegram/router"
+	"salary-bot/pkg/i18n"
+
+	"gopkg.in/telebot.v3"
+)
+
+// /export — выгрузка смен и выплат за месяц или диапазон дат в CSV или XLSX.
+func (h *Handler) handleExport(c telebot.Context) error {
+	h.cancelFlow(c.Chat().ID)
+	title, markup := exportKeyboard(h.lang(c), h.now(c).Year())
+	return c.Send(title, markup)
+}
+
+func exportKeyboard(l i18n.Lang, year int) (string, *telebot.ReplyMarkup) {
+	title, markup := keyboards.BuildMonthKeyboardFor(l, year, "export_month", "export_year")
+	markup.InlineKeyboard = append(markup.InlineKeyboard, []telebot.InlineButton{
+		*markup.Data(l.T("btn.range"), "export_range").Inline(),
+	})
+	return l.T("export.title", title), markup
+}
+
+func (h *Handler) registerExport(r *router.CallbackRouter) {
+	r.Register("export_year", func(c telebot.Context, payload string) error {
+		y, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil
+		}
+		title, markup := exportKeyboard(h.lang(c), y)
+		return middleware.EditOrSend(c, title, markup)
+	})
+	r.Register("export_month", func(c telebot.Context, payload string) error {
+		y, m, ok := keyboards.ParseMonth(payload)
+		if !ok {
+			return nil
+		}
+		from := time.Date(y, time.Month(m), 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(y, time.Month(m)+1, 0, 0, 0, 0, 0, time.UTC)
+		return h.askExportFormat(c, from, to)
+	})
+	r.Register("export_range", func(c telebot.Context, payload string) error {
+		if h.Calendar == nil {
+			return nil
+		}
+		l := h.lang(c)
+		_ = c.Send(l.T("export.pick_start"))
+		return h.Calendar.ShowCalendar(c, func(start time.Time, c telebot.Context) error {
+			_ = c.Send(l.T("range.pick_end", l.Date(start)))
+			return h.Calendar.ShowCalendar(c, func(end time.Time, c telebot.Context) error {
+				if end.Before(start) {
+					start, end = end, start
+				}
+				return h.askExportFormat(c, start, end)
+			})
+		})
+	})
+	r.Register("export_file", h.sendExport)
+}
+
+// askExportFormat предлагает формат файла; период передаётся в payload
+// кнопок, поэтому выгрузку можно скачать повторно в другом формате.
+func (h *Handler) askExportFormat(c telebot.Context, from, to time.Time) error {
+	period := from.Format("2006-01-02") + "|" + to.Format("2006-01-02")
+	markup := &telebot.ReplyMarkup{}
+	btnCSV := markup.Data("📄 CSV", "export_file", service.FormatCSV+"|"+period)
+	btnXLSX := markup.Data("📊 Excel (XLSX)", "export_file", service.FormatXLSX+"|"+period)
+	markup.Inline(markup.Row(btnCSV, btnXLSX))
+	l := h.lang(c)
+	return middleware.EditOrSend(c, l.T("export.format", l.Date(from), l.Date(to)), markup)
+}
+
+var exportMIME = map[string]string{
+	service.FormatCSV:  "text/csv",
+	service.FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+func (h *Handler) sendExport(c telebot.Context, payload string) error {
+	parts := strings.Split(payload, "|")
+	if len(parts) != 3 {
+		return nil
+	}
+	format := parts[0]
+	from, err1 := time.Parse("2006-01-02", parts[1])
+	to, err2 := time.Parse("2006-01-02", parts[2])
+	if err1 != nil || err2 != nil || exportMIME[format] == "" {
+		return nil
+	}
+	l := h.lang(c)
+	_ = c.Send(l.T("export.preparing", l.Date(from), l.Date(to)))
+	data, err := h.Exports.Export(format, int(c.Sender().ID), from, to, l)
+	if err != nil {
+		log.Printf("[export] %s chat=%d: %v", format, c.Chat().ID, err)
+		return c.Send(l.T("export.failed", errText(l, err)))
+	}
+	doc := &telebot.Document{
+		File:     telebot.FromReader(bytes.NewReader(data)),
+		FileName: "salary_" + parts[1] + "_" + parts[2] + "." + format,
+		MIME:     exportMIME[format],
+	}
+	return c.Send(doc)
+}
@@ -9,15 +9,20 @@ import (
 	"salary-bot/internal/app/service"
 	"salary-bot/internal/delivery/telegram/keyboards"
 	"salary-bot/internal/delivery/telegram/router"
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/i18n"
 
 	"gopkg.in/telebot.v3"
 )
 
-
-func RegisterSalary(r *router.CallbackRouter, shifts *service.ShiftServiceImpl) {
+// RegisterSalary регистрирует кнопки экрана зарплаты. target возвращает ID
+// сотрудника, чью зарплату показывать (для менеджера — выбранного им
+// сотрудника), lang — язык сообщений пользователю, now — текущее время в
+// его поясе.
+func RegisterSalary(r *router.CallbackRouter, shifts *service.ShiftServiceImpl, target func(telebot.Context) int, lang func(telebot.Context) i18n.Lang, now func(telebot.Context) time.Time) {
 	r.Register("salary_other_month", func(c telebot.Context, payload string) error {
-		year := time.Now().Year()
-		title, markup := keyboards.BuildMonthKeyboard(year)
+		year := now(c).Year()
+		title, markup := keyboards.BuildMonthKeyboard(lang(c), year)
 		if err := c.Edit(title, markup); err != nil {
 			return c.Send(title, markup)
 		}
@@ -27,7 +32,7 @@ func RegisterSalary(r *router.CallbackRouter, shifts *service.ShiftServiceImpl)
 	r.Register("month_prev", func(c telebot.Context, payload string) error {
 		y, _ := strconv.Atoi(payload)
 		y--
-		title, markup := keyboards.BuildMonthKeyboard(y)
+		title, markup := keyboards.BuildMonthKeyboard(lang(c), y)
 		if err := c.Edit(title, markup); err != nil {
 			return c.Send(title, markup)
 		}
@@ -37,7 +42,7 @@ func RegisterSalary(r *router.CallbackRouter, shifts *service.ShiftServiceImpl)
 	r.Register("month_next", func(c telebot.Context, payload string) error {
 		y, _ := strconv.Atoi(payload)
 		y++
-		title, markup := keyboards.BuildMonthKeyboard(y)
+		title, markup := keyboards.BuildMonthKeyboard(lang(c), y)
 		if err := c.Edit(title, markup); err != nil {
 			return c.Send(title, markup)
 		}
@@ -51,17 +56,53 @@ func RegisterSalary(r *router.CallbackRouter, shifts *service.ShiftServiceImpl)
 		}
 		y, _ := strconv.Atoi(parts[0])
 		m, _ := strconv.Atoi(parts[1])
-		empID := int(c.Sender().ID)
+		if m < 1 || m > 12 {
+			return nil
+		}
+		l := lang(c)
+		empID := target(c)
 		from := time.Date(y, time.Month(m), 1, 0, 0, 0, 0, time.UTC)
 		to := time.Date(y, time.Month(m)+1, 0, 0, 0, 0, 0, time.UTC)
 		total, err := shifts.CalculateSalary(empID, from, to)
 		if err != nil {
-			return c.Send("Ошибка при расчёте зарплаты: " + err.Error())
+			return c.Send(l.T("err.salary", err.Error()))
 		}
-		msg := fmt.Sprintf("Зарплата за %02d.%04d: %.2f", int(m), y, total)
+		msg := l.T("salary.month", l.Month(time.Month(m))+" "+strconv.Itoa(y), l.Totals(total))
 		if err := c.Edit(msg); err != nil {
 			return c.Send(msg)
 		}
 		return nil
 	})
+
+	r.Register("payout_history", func(c telebot.Context, payload string) error {
+		l := lang(c)
+		empID := target(c)
+		payouts, err := shifts.GetPayouts(empID, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Now().AddDate(10, 0, 0))
+		if err != nil {
+			return c.Send(l.T("err.payouts", err.Error()))
+		}
+		if len(payouts) == 0 {
+			return c.Send(l.T("payouts.none"))
+		}
+		// показываем последние 10 выплат, самые свежие внизу
+		if len(payouts) > 10 {
+			payouts = payouts[len(payouts)-10:]
+		}
+		var b strings.Builder
+		b.WriteString(l.T("payouts.recent") + "\n")
+		for _, p := range payouts {
+			fmt.Fprintf(&b, "%s — %s", l.Date(p.Date), l.Money(p.Currency, p.Amount))
+			if p.Note != "" {
+				b.WriteString(" (" + service.PayoutNote(l, p.Note) + ")")
+			}
+			switch p.Status {
+			case domain.PayoutUnconfirmed:
+				b.WriteString(" ⏳")
+			case domain.PayoutDisputed:
+				b.WriteString(" ❗")
+			}
+			b.WriteString("\n")
+		}
+		return c.Send(b.String())
+	})
 }
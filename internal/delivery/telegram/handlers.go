@@ -1,15 +1,21 @@
 package telegram
 
 import (
+	"errors"
 	"log"
-	"salary-bot/internal/app/service"
-	"salary-bot/pkg/calendar"
-	"salary-bot/internal/delivery/telegram/router"
-	"salary-bot/internal/delivery/telegram/flows"
 	"strconv"
 	"strings"
 	"time"
 
+	"salary-bot/internal/app/service"
+	"salary-bot/internal/delivery/telegram/flows"
+	"salary-bot/internal/delivery/telegram/middleware"
+	"salary-bot/internal/delivery/telegram/router"
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/calendar"
+	"salary-bot/pkg/i18n"
+	"salary-bot/pkg/money"
+
 	"gopkg.in/telebot.v3"
 )
 
@@ -19,401 +25,550 @@ type Handler struct {
 	Async         *service.AsyncService
 	Employees     *service.EmployeeService
 	Calendar      *calendar.CalendarController
-	waitingAmount map[int64]time.Time 
-	waitingPayout map[int64]bool      
+	Conversations *service.ConversationService
+	Rates         *service.RateService
+	Undo          *service.UndoService
+	Audit         *service.AuditService
+	Exports       *service.ExportService
+	Payslips      *service.PayslipService
+	// Allowed — allowlist Telegram ID; пустой — бот доступен всем.
+	Allowed map[int64]bool
+	// Locale — язык для пользователей, чей язык Telegram не поддерживается.
+	Locale i18n.Lang
 }
 
-
 func (h *Handler) Register() {
-	h.Bot.Handle("/start", h.handleStart)
-	h.Bot.Handle("/employees", h.handleEmployees)
-	h.Bot.Handle("/resetme", h.handleResetMe)
+	auth := middleware.NewAuth(h.resolveSender, h.Allowed)
+	auth.Lang = h.lang
+	h.Bot.Use(auth.Middleware)
+
+	commands := []struct {
+		cmd    string
+		access middleware.Access
+		fn     telebot.HandlerFunc
+	}{
+		{"/start", middleware.AccessEmployee, h.handleStart},
+		{"/employees", middleware.AccessManager, h.handleEmployees},
+		{"/resetme", middleware.AccessEmployee, h.handleResetMe},
+		{"/hourly", middleware.AccessEmployee, h.handleHourly},
+		{"/rate", middleware.AccessEmployee, h.handleRate},
+		{"/shifts", middleware.AccessEmployee, h.handleShifts},
+		{"/undo", middleware.AccessEmployee, h.handleUndo},
+		{"/history", middleware.AccessEmployee, h.handleHistory},
+		{"/export", middleware.AccessEmployee, h.handleExport},
+		{"/import", middleware.AccessEmployee, h.handleImport},
+		{"/remind", middleware.AccessEmployee, h.handleRemind},
+		{"/currency", middleware.AccessEmployee, h.handleCurrency},
+		{"/lang", middleware.AccessEmployee, h.handleLang},
+		{"/timezone", middleware.AccessEmployee, h.handleTimeZone},
+	}
+	for _, cmd := range commands {
+		auth.Command(cmd.cmd, cmd.access)
+		h.Bot.Handle(cmd.cmd, cmd.fn)
+	}
+	h.Bot.Handle(telebot.OnDocument, h.handleDocument)
+	h.Bot.Handle(telebot.OnLocation, h.handleLocation)
+
+	if h.Calendar != nil && h.Calendar.Lang == nil {
+		h.Calendar.Lang = h.lang
+	}
+	if h.Calendar != nil && h.Calendar.Now == nil {
+		h.Calendar.Now = h.now
+	}
 
-	
 	r := router.New()
 	r.CalDelegate = h.RegisterHandlersCallback
-	flows.RegisterSalary(r, h.Shifts)
+	h.registerCallbacks(r)
+	flows.RegisterSalary(r, h.Shifts, h.targetID, h.lang, h.now)
+	h.registerShiftBrowser(r)
+	r.Register("undo", h.handleUndoCallback)
+	h.registerExport(r)
+	h.registerImport(r)
+	h.registerPayslip(r)
+	h.registerEveningPrompt(r)
+	h.registerManager(r)
+	h.registerApprovals(r)
+	h.registerPayoutConfirm(r)
+
+	// Все кнопки проходят через роутер; ключи без записи в матрице
+	// доступа middleware отклоняет.
+	for _, key := range r.Keys() {
+		auth.Callback(key, middleware.AccessEmployee)
+	}
+	auth.Callback("act_as", middleware.AccessManager)
+	auth.Callback("act_self", middleware.AccessManager)
+	auth.Callback("shift_approve", middleware.AccessManager)
+	auth.Callback("shift_reject", middleware.AccessManager)
+	auth.Callback("shift_approve_all", middleware.AccessManager)
 
-	
 	h.Bot.Handle(telebot.OnCallback, func(c telebot.Context) error {
-        
-        raw := c.Data()
-        raw = strings.TrimPrefix(raw, "\f")
-        key := raw
-        if i := strings.IndexByte(raw, '|'); i >= 0 {
-            key = raw[:i]
-        }
-		
-		log.Printf("[callback] raw=%q key=%q", raw, key)
-		
-		_ = c.Respond()
-
-		// Handle calendar callbacks by prefix and exit early
-        if strings.HasPrefix(key, "cal_") {
-            if h.Calendar != nil {
-                return h.RegisterHandlersCallback(c)
-            }
-            return nil
-        }
-
-		if handled, err := func() (bool, error) { return r.Dispatch(c) }(); handled {
-			return err
+		_, err := r.Dispatch(c)
+		return err
+	})
+
+	h.Bot.Handle(telebot.OnText, func(c telebot.Context) error {
+		chatID := c.Chat().ID
+		txt := strings.TrimSpace(strings.ToLower(c.Text()))
+		l := h.lang(c)
+
+		switch txt {
+		case "отмена", "cancel", "/cancel", "стоп", "stop", "/stop":
+			h.cancelFlow(chatID)
+			return c.Send(l.T("cancelled.menu"))
 		}
-		
-		switch key {
-		case "resetme_confirm":
-			empID := int(c.Sender().ID)
-			if err := h.Shifts.ResetEmployeeData(empID); err != nil {
-				return c.Send("Ошибка при сбросе данных: "+err.Error())
+
+		// кнопки меню принимаются на любом языке: клавиатура могла
+		// остаться от прежнего
+		if i18n.Is(c.Text(), "menu.add_shift") {
+			markup := &telebot.ReplyMarkup{}
+			btnCancel := markup.Data(l.T("btn.cancel"), "cancel_flow")
+			btnToday := markup.Data(l.T("btn.today"), "addshift_today")
+			btnOther := markup.Data(l.T("btn.other_date"), "addshift_other")
+			markup.Inline(markup.Row(btnToday, btnOther), markup.Row(btnCancel))
+			h.cancelFlow(chatID)
+			return c.Send(h.targetHeader(c)+l.T("shift.is_today"), markup)
+		}
+		if i18n.Is(c.Text(), "menu.salary") {
+			h.cancelFlow(chatID)
+			empID := h.targetID(c)
+			now := h.now(c)
+			mFrom := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+			mTo := time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+			monthTotal, err := h.Shifts.CalculateSalary(empID, mFrom, mTo)
+			if err != nil {
+				return c.Send(l.T("err.salary", errText(l, err)))
 			}
-			// clear any waiting states
-			if h.waitingAmount != nil { delete(h.waitingAmount, c.Chat().ID) }
-			if h.waitingPayout != nil { delete(h.waitingPayout, c.Chat().ID) }
-			if err := c.Edit("Ваши данные удалены.", &telebot.ReplyMarkup{}); err != nil {
-				_ = c.Send("Ваши данные удалены.")
+			unpaidTotal, err := h.Shifts.CalculateUnpaidSalary(empID)
+			if err != nil {
+				return c.Send(l.T("err.data", errText(l, err)))
 			}
-			return nil
-		case "addshift_today":
-			date := time.Now()
-			log.Printf("[callback] addshift_today chat=%d date=%s", c.Chat().ID, date.Format("2006-01-02"))
-			m := &telebot.ReplyMarkup{}
-			btnCancel := m.Data("❌ Отмена", "cancel_flow")
-			m.Inline(m.Row(btnCancel))
-			if err := c.Edit("Введите сумму для смены "+date.Format("02.01.2006")+":", m); err != nil {
-				_ = c.Send("Введите сумму для смены "+date.Format("02.01.2006")+":", m)
+			pendingTotal, err := h.Shifts.CalculatePendingSalary(empID)
+			if err != nil {
+				return c.Send(l.T("err.data", errText(l, err)))
 			}
-			if h.waitingAmount == nil {
-				h.waitingAmount = make(map[int64]time.Time)
+			unconfirmed, disputed, err := h.Shifts.UnconfirmedPayouts(empID)
+			if err != nil {
+				return c.Send(l.T("err.data", errText(l, err)))
 			}
-			h.waitingAmount[c.Chat().ID] = date
-			log.Printf("[state] waitingAmount set for chat=%d date=%s", c.Chat().ID, date.Format("2006-01-02"))
-			return nil
-		case "addshift_other":
-			if h.Calendar != nil {
-				h.Calendar.OnDate = func(date time.Time, c telebot.Context) error {
-					log.Printf("[callback] other_day_shift picked date chat=%d date=%s", c.Chat().ID, date.Format("2006-01-02"))
-					m := &telebot.ReplyMarkup{}
-					btnCancel := m.Data("❌ Отмена", "cancel_flow")
-					m.Inline(m.Row(btnCancel))
-					if err := c.Edit("Введите сумму для смены "+date.Format("02.01.2006")+":", m); err != nil {
-						_ = c.Send("Введите сумму для смены "+date.Format("02.01.2006")+":", m)
-					}
-					if h.waitingAmount == nil {
-						h.waitingAmount = make(map[int64]time.Time)
-					}
-					h.waitingAmount[c.Chat().ID] = date
-					log.Printf("[state] waitingAmount set for chat=%d date=%s", c.Chat().ID, date.Format("2006-01-02"))
-					return nil
-				}
-				return h.Calendar.ShowCalendar(c)
+			markup := &telebot.ReplyMarkup{}
+			btnOtherMonth := markup.Data(l.T("btn.other_month"), "salary_other_month")
+			btnRange := markup.Data(l.T("btn.range"), "salary_range")
+			btnPayouts := markup.Data(l.T("btn.payout_history"), "payout_history")
+			btnPayslip := markup.Data(l.T("btn.payslip"), "payslip")
+			markup.Inline(markup.Row(btnOtherMonth), markup.Row(btnRange), markup.Row(btnPayouts), markup.Row(btnPayslip))
+			msg := h.targetHeader(c) + l.T("salary.this_month", l.Totals(monthTotal)) + "\n" +
+				l.T("salary.unpaid", l.Totals(unpaidTotal))
+			if !pendingTotal.IsZero() {
+				msg += "\n" + l.T("salary.pending", l.Totals(pendingTotal))
 			}
-			return nil
-		case "cancel_flow":
-			
-			if h.waitingAmount != nil {
-				delete(h.waitingAmount, c.Chat().ID)
+			if !unconfirmed.IsZero() {
+				msg += "\n" + l.T("salary.unconfirmed", l.Totals(unconfirmed))
 			}
-			if h.waitingPayout != nil {
-				delete(h.waitingPayout, c.Chat().ID)
+			if !disputed.IsZero() {
+				msg += "\n" + l.T("salary.disputed", l.Totals(disputed))
 			}
-			if err := c.Edit("Действие отменено."); err != nil {
-				_ = c.Send("Действие отменено.")
+			return c.Send(msg, markup)
+		}
+		if i18n.Is(c.Text(), "menu.payout") {
+			return h.startPayout(c)
+		}
+
+		conv, err := h.Conversations.Get(chatID)
+		if err != nil {
+			log.Printf("[state] get chat=%d: %v", chatID, err)
+			return c.Send(l.T("err.generic", errText(l, err)))
+		}
+		switch conv.State {
+		case domain.StateAwaitShiftAmount:
+			return h.handleShiftAmount(c, conv)
+		case domain.StateAwaitPayoutAmount:
+			return h.handlePayoutAmount(c, conv)
+		case domain.StateAwaitShiftTimes:
+			return h.handleShiftTimes(c, conv)
+		case domain.StateAwaitShiftEditAmount:
+			return h.handleShiftEditAmount(c, conv)
+		case domain.StateAwaitTimeZone:
+			return h.handleTimeZoneText(c, conv)
+		}
+		return nil
+	})
+}
+
+// registerCallbacks — кнопки главного меню и общих шагов сценариев.
+func (h *Handler) registerCallbacks(r *router.CallbackRouter) {
+	r.Register("resetme_confirm", func(c telebot.Context, payload string) error {
+		empID := int(c.Sender().ID)
+		l := h.lang(c)
+		if err := h.Shifts.As(c.Sender().ID).ResetEmployeeData(empID); err != nil {
+			return c.Send(l.T("reset.failed", errText(l, err)))
+		}
+		h.cancelFlow(c.Chat().ID)
+		if err := c.Edit(l.T("reset.done"), &telebot.ReplyMarkup{}); err != nil {
+			_ = c.Send(l.T("reset.done"))
+		}
+		return nil
+	})
+	r.Register("addshift_today", func(c telebot.Context, payload string) error {
+		date := domain.DateOf(h.now(c))
+		log.Printf("[callback] addshift_today chat=%d date=%s", c.Chat().ID, date.Format("2006-01-02"))
+		return h.askShiftAmount(c, date)
+	})
+	r.Register("addshift_other", func(c telebot.Context, payload string) error {
+		if h.Calendar != nil {
+			return h.Calendar.ShowCalendar(c, func(date time.Time, c telebot.Context) error {
+				log.Printf("[callback] other_day_shift picked date chat=%d date=%s", c.Chat().ID, date.Format("2006-01-02"))
+				return h.askShiftAmount(c, date)
+			})
+		}
+		return nil
+	})
+	r.Register("shift_at_rate", func(c telebot.Context, payload string) error {
+		return h.handleShiftAtRate(c)
+	})
+	r.Register("cancel_flow", func(c telebot.Context, payload string) error {
+		h.cancelFlow(c.Chat().ID)
+		if err := c.Edit(h.lang(c).T("cancelled")); err != nil {
+			_ = c.Send(h.lang(c).T("cancelled"))
+		}
+		return nil
+	})
+	r.Register("payout_all", func(c telebot.Context, payload string) error {
+		empID := h.targetID(c)
+		l := h.lang(c)
+		h.cancelFlow(c.Chat().ID)
+		unpaid, err := h.Shifts.CalculateUnpaidSalary(empID)
+		if err != nil {
+			return c.Send(l.T("err.data", errText(l, err)))
+		}
+		currency := h.payoutCurrency(empID, unpaid, money.Currency(payload))
+		payoutID, err := h.Shifts.As(c.Sender().ID).MarkShiftsPaid(empID, currency, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Now().AddDate(10, 0, 0), c.Sender().ID)
+		if err != nil {
+			if err := c.Edit(l.T("payout.all_failed", errText(l, err))); err != nil {
+				_ = c.Send(l.T("payout.all_failed", errText(l, err)))
 			}
 			return nil
-		case "payout_all":
-			empID := int(c.Sender().ID)
-			err := h.Shifts.MarkShiftsPaid(empID, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Now().AddDate(10, 0, 0))
-			if err != nil {
-				if err := c.Edit("Ошибка при полной выплате: " + err.Error()); err != nil {
-					_ = c.Send("Ошибка при полной выплате: " + err.Error())
-				}
-				return nil
-			}
-			if err := c.Edit("Выплачено всё!"); err != nil {
-				_ = c.Send("Выплачено всё!")
-			}
-			
-			if h.waitingPayout != nil {
-				delete(h.waitingPayout, c.Chat().ID)
+		}
+		note := ""
+		if payoutID != 0 {
+			// запрос подтверждения сотруднику заменяет простое уведомление
+			if note = h.requestPayoutConfirmation(l, payoutID); note == "" {
+				h.notifyTarget(c, empID, func(l i18n.Lang) string {
+					return l.T("notify.payout", l.Money(currency, unpaid[currency]))
+				})
 			}
-			return nil
-		case "salary_range":
-			
-			if h.Calendar != nil {
-				c.Send("Выберите начальную дату диапазона")
-				h.Calendar.OnDate = func(start time.Time, c telebot.Context) error {
-					_ = c.Send("Начало: " + start.Format("02.01.2006") + "\nТеперь выберите конечную дату")
-					
-					h.Calendar.OnDate = func(end time.Time, c telebot.Context) error {
-						if end.Before(start) {
-							start, end = end, start
-						}
-						empID := int(c.Sender().ID)
-						from := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
-						to := time.Date(end.Year(), end.Month(), end.Day(), 23, 59, 59, 0, time.UTC)
-						
-						shifts, err := h.Shifts.GetShifts(empID, from, to)
-						if err != nil {
-							return c.Send("Ошибка при получении смен: " + err.Error())
-						}
-						var total float64
-						for _, s := range shifts {
-							total += s.Amount
+		}
+		return middleware.EditOrSend(c, l.T("payout.all_done")+note, h.recordUndo(c, empID, domain.UndoPayout, payoutID))
+	})
+	r.Register("salary_range", func(c telebot.Context, payload string) error {
+		if h.Calendar != nil {
+			l := h.lang(c)
+			c.Send(l.T("range.pick_start"))
+			return h.Calendar.ShowCalendar(c, func(start time.Time, c telebot.Context) error {
+				_ = c.Send(l.T("range.pick_end", l.Date(start)))
+				return h.Calendar.ShowCalendar(c, func(end time.Time, c telebot.Context) error {
+					if end.Before(start) {
+						start, end = end, start
+					}
+					empID := h.targetID(c)
+					from := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+					to := time.Date(end.Year(), end.Month(), end.Day(), 23, 59, 59, 0, time.UTC)
+
+					shifts, err := h.Shifts.GetShifts(empID, from, to)
+					if err != nil {
+						return c.Send(l.T("err.shifts", errText(l, err)))
+					}
+					total := money.Totals{}
+					for _, s := range shifts {
+						if s.Approved() {
+							total.Add(s.Currency, s.Amount)
 						}
-						return c.Send("Заработано за период " + start.Format("02.01.2006") + " - " + end.Format("02.01.2006") + ": " + strconv.FormatFloat(total, 'f', 2, 64))
 					}
-					return h.Calendar.ShowCalendar(c)
-				}
-				return h.Calendar.ShowCalendar(c)
-			}
-			return nil
+					return c.Send(h.targetHeader(c) + l.T("range.total", l.Date(start), l.Date(end), l.Totals(total)))
+				})
+			})
 		}
 		return nil
 	})
+	r.Register("payout_start", func(c telebot.Context, payload string) error {
+		return h.startPayout(c)
+	})
+}
 
-	
-	h.Bot.Handle(telebot.OnText, func(c telebot.Context) error {
-		chatID := c.Chat().ID
-		
-		txt := strings.TrimSpace(strings.ToLower(c.Text()))
-
-		
-		switch txt {
-		case "отмена", "cancel", "/cancel", "стоп", "/stop":
-			if h.waitingAmount != nil {
-				delete(h.waitingAmount, chatID)
-			}
-			if h.waitingPayout != nil {
-				delete(h.waitingPayout, chatID)
-			}
-			return c.Send("Действие отменено. Выберите команду из меню.")
+func (h *Handler) startPayout(c telebot.Context) error {
+	l := h.lang(c)
+	markup := &telebot.ReplyMarkup{}
+	btnCancel := markup.Data(l.T("btn.cancel"), "cancel_flow")
+	var rows []telebot.Row
+	unpaid, err := h.Shifts.CalculateUnpaidSalary(h.targetID(c))
+	if err != nil {
+		return c.Send(l.T("err.data", errText(l, err)))
+	}
+	if len(unpaid) > 1 {
+		// выплата всегда в одной валюте: по кнопке на каждую
+		for _, cur := range unpaid.Currencies() {
+			rows = append(rows, markup.Row(markup.Data(l.T("btn.payout_all_in", string(cur), l.Money(cur, unpaid[cur])), "payout_all", string(cur))))
 		}
-		        
-        
-        if c.Text() == "➕ Добавить смену" {
-            markup := &telebot.ReplyMarkup{}
-            btnCancel := markup.Data("❌ Отмена", "cancel_flow")
-            btnToday := markup.Data("📅 Сегодня", "addshift_today")
-            btnOther := markup.Data("📆 Другая дата", "addshift_other")
-            markup.Inline(markup.Row(btnToday, btnOther), markup.Row(btnCancel))
-            
-            if h.waitingPayout != nil {
-                delete(h.waitingPayout, chatID)
-            }
-            if h.waitingAmount != nil {
-                delete(h.waitingAmount, chatID)
-            }
-            return c.Send("Это сегодняшняя смена?", markup)
-        }
-        if c.Text() == "💰 Зарплата" {
-            
-            if h.waitingPayout != nil {
-                delete(h.waitingPayout, chatID)
-            }
-            if h.waitingAmount != nil {
-                delete(h.waitingAmount, chatID)
-            }
-            empID := int(c.Sender().ID)
-            now := time.Now()
-            mFrom := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
-            mTo := time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, time.UTC)
-            monthTotal, err := h.Shifts.CalculateSalary(empID, mFrom, mTo)
-            if err != nil {
-                return c.Send("Ошибка при расчёте зарплаты: "+err.Error())
-            }
-            allFrom := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
-            allTo := time.Now().AddDate(10, 0, 0)
-            allShifts, err := h.Shifts.GetShifts(empID, allFrom, allTo)
-            if err != nil {
-                return c.Send("Ошибка при получении данных: "+err.Error())
-            }
-            var unpaidTotal float64
-            for _, s := range allShifts { if !s.Paid { unpaidTotal += s.Amount } }
-            markup := &telebot.ReplyMarkup{}
-            btnOtherMonth := markup.Data("📊 Другой месяц", "salary_other_month")
-            btnRange := markup.Data("🗓️ Диапазон дат", "salary_range")
-            markup.Inline(markup.Row(btnOtherMonth), markup.Row(btnRange))
-            msg := "Зарплата за этот месяц: "+strconv.FormatFloat(monthTotal, 'f', 2, 64)+"\n"+
-                "Невыплачено всего: "+strconv.FormatFloat(unpaidTotal, 'f', 2, 64)
-            return c.Send(msg, markup)
-        }
-        if c.Text() == "💸 Выплата" {
-            markup := &telebot.ReplyMarkup{}
-            btnCancel := markup.Data("❌ Отмена", "cancel_flow")
-            btnAll := markup.Data("✅ Выплатить всё", "payout_all")
-            markup.Inline(markup.Row(btnAll), markup.Row(btnCancel))
-            
-            if h.waitingAmount != nil { delete(h.waitingAmount, chatID) }
-            if h.waitingPayout == nil { h.waitingPayout = make(map[int64]bool) }
-            h.waitingPayout[chatID] = true
-            return c.Send("Сколько выплатить? Введите сумму, выберите 'Выплатить всё' или напишите 'отмена' для выхода.", markup)
-        }
-
-        
-        if h.waitingAmount != nil {
-            if date, ok := h.waitingAmount[chatID]; ok {
-                amount, err := strconv.ParseFloat(c.Text(), 64)
-                if err != nil {
-                    m := &telebot.ReplyMarkup{}
-                    btnCancel := m.Data("❌ Отмена", "cancel_flow")
-                    m.Inline(m.Row(btnCancel))
-                    return c.Send("Некорректная сумма. Попробуйте ещё раз.", m)
-                }
-                if amount < 1 {
-                    m := &telebot.ReplyMarkup{}
-                    btnCancel := m.Data("❌ Отмена", "cancel_flow")
-                    m.Inline(m.Row(btnCancel))
-                    return c.Send("Сумма должна быть не менее 1. Введите сумму ещё раз.", m)
-                }
-                if err := h.Shifts.AddShift(int(c.Sender().ID), date, amount); err != nil {
-                    return c.Send("Ошибка при добавлении смены: "+err.Error())
-                }
-                delete(h.waitingAmount, chatID)
-                return c.Send("Смена добавлена!")
-            }
-        }
-        
-        if h.waitingPayout != nil {
-            if _, ok := h.waitingPayout[chatID]; ok {
-                empID := int(c.Sender().ID)
-                amount, err := strconv.ParseFloat(c.Text(), 64)
-                if err != nil {
-                    m := &telebot.ReplyMarkup{}
-                    btnCancel := m.Data("❌ Отмена", "cancel_flow")
-                    m.Inline(m.Row(btnCancel))
-                    return c.Send("Некорректная сумма. Попробуйте ещё раз.", m)
-                }
-                if amount < 1 {
-                    m := &telebot.ReplyMarkup{}
-                    btnCancel := m.Data("❌ Отмена", "cancel_flow")
-                    m.Inline(m.Row(btnCancel))
-                    return c.Send("Сумма выплаты должна быть не менее 1. Введите сумму ещё раз.", m)
-                }
-                
-                allFrom := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
-                allTo := time.Now().AddDate(10, 0, 0)
-                allShifts, err := h.Shifts.GetShifts(empID, allFrom, allTo)
-                if err != nil {
-                    return c.Send("Ошибка при получении данных: "+err.Error())
-                }
-                var unpaidTotal float64
-                for _, s := range allShifts {
-                    if !s.Paid { unpaidTotal += s.Amount }
-                }
-                if amount > unpaidTotal+1e-9 {
-                    m := &telebot.ReplyMarkup{}
-                    btnCancel := m.Data("❌ Отмена", "cancel_flow")
-                    m.Inline(m.Row(btnCancel))
-                    return c.Send("Нельзя выплатить больше, чем заработано. Доступно к выплате: "+strconv.FormatFloat(unpaidTotal, 'f', 2, 64), m)
-                }
-                if err := h.Shifts.MarkShiftsPaidAmount(empID, amount); err != nil {
-                    return c.Send("Ошибка при выплате: "+err.Error())
-                }
-                delete(h.waitingPayout, chatID)
-                return c.Send("Выплата на сумму "+strconv.FormatFloat(amount, 'f', 2, 64)+" проведена!")
-            }
-        }
-        return nil
-    })
+	} else {
+		rows = append(rows, markup.Row(markup.Data(l.T("btn.payout_all"), "payout_all")))
+	}
+	markup.Inline(append(rows, markup.Row(btnCancel))...)
+	if err := h.Conversations.Set(c.Chat().ID, domain.StateAwaitPayoutAmount, nil); err != nil {
+		return c.Send(l.T("err.generic", errText(l, err)))
+	}
+	return c.Send(h.targetHeader(c)+l.T("payout.ask"), markup)
 }
 
+// cancelFlow — единственный способ сбросить незавершённый сценарий чата.
+func (h *Handler) cancelFlow(chatID int64) {
+	if err := h.Conversations.Cancel(chatID); err != nil {
+		log.Printf("[state] cancel chat=%d: %v", chatID, err)
+	}
+}
 
-func (h *Handler) RegisterHandlersCallback(c telebot.Context) error {
-    if h.Calendar != nil {
-        raw := c.Data()
-        raw = strings.TrimPrefix(raw, "\f")
-        split := strings.SplitN(raw, "|", 2)
-        if len(split) != 2 {
-            return nil
-        }
-        payload := split[1]
-		switch split[0] {
-		case "cal_day":
-			parts := calendar.SplitDateData(payload)
-			if len(parts) != 3 {
-				return c.Send("Ошибка даты", &telebot.ReplyMarkup{})
-			}
-			day, _ := strconv.Atoi(parts[0])
-			month, _ := strconv.Atoi(parts[1])
-			year, _ := strconv.Atoi(parts[2])
-			date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
-			if h.Calendar.OnDate != nil {
-				return h.Calendar.OnDate(date, c)
-			}
-			return c.Send("Ошибка даты", &telebot.ReplyMarkup{})
-		case "cal_prev":
-			parts := calendar.SplitDateData(payload)
-			if len(parts) != 2 {
-				return c.Send("Ошибка месяца", &telebot.ReplyMarkup{})
-			}
-			month, _ := strconv.Atoi(parts[0])
-			year, _ := strconv.Atoi(parts[1])
-			if month < 1 {
-				month = 12
-				year--
-			}
-			return calendar.SendCalendar(c, year, month)
-		case "cal_next":
-			parts := calendar.SplitDateData(payload)
-			if len(parts) != 2 {
-				return c.Send("Ошибка месяца", &telebot.ReplyMarkup{})
-			}
-			month, _ := strconv.Atoi(parts[0])
-			year, _ := strconv.Atoi(parts[1])
-			if month > 12 {
-				month = 1
-				year++
-			}
-			return calendar.SendCalendar(c, year, month)
-		}
+func cancelMarkupIn(l i18n.Lang) *telebot.ReplyMarkup {
+	m := &telebot.ReplyMarkup{}
+	btnCancel := m.Data(l.T("btn.cancel"), "cancel_flow")
+	m.Inline(m.Row(btnCancel))
+	return m
+}
+
+func (h *Handler) askShiftAmount(c telebot.Context, date time.Time) error {
+	if e, _ := h.target(c); e.IsHourly() {
+		return h.askShiftTimes(c, date)
+	}
+	l := h.lang(c)
+	err := h.Conversations.Set(c.Chat().ID, domain.StateAwaitShiftAmount, map[string]string{
+		"date": date.Format("2006-01-02"),
+	})
+	if err != nil {
+		return c.Send(l.T("err.generic", errText(l, err)))
+	}
+	log.Printf("[state] await_shift_amount set for chat=%d date=%s", c.Chat().ID, date.Format("2006-01-02"))
+	text := l.T("shift.ask_amount", l.Date(date))
+	markup := cancelMarkupIn(l)
+	if rate, ok := h.shiftRate(c, date); ok {
+		rateText := l.Money(h.Shifts.EmployeeCurrency(h.targetID(c)), rate)
+		text = l.T("shift.ask_amount_rate", l.Date(date), rateText)
+		markup = &telebot.ReplyMarkup{}
+		btnRate := markup.Data(l.T("btn.at_rate", rateText), "shift_at_rate")
+		btnCancel := markup.Data(l.T("btn.cancel"), "cancel_flow")
+		markup.Inline(markup.Row(btnRate), markup.Row(btnCancel))
+	}
+	if err := c.Edit(text, markup); err != nil {
+		_ = c.Send(text, markup)
 	}
 	return nil
 }
 
+func (h *Handler) handleShiftAmount(c telebot.Context, conv domain.Conversation) error {
+	l := h.lang(c)
+	date, err := time.Parse("2006-01-02", conv.Data["date"])
+	if err != nil {
+		h.cancelFlow(conv.ChatID)
+		return c.Send(l.T("err.date"))
+	}
+	amount, err := money.Parse(c.Text())
+	if err != nil {
+		return c.Send(l.T("shift.amount_invalid"), cancelMarkupIn(l))
+	}
+	if amount < money.FromMinor(100) {
+		return c.Send(l.T("shift.amount_min"), cancelMarkupIn(l))
+	}
+	if ok, err := h.Conversations.Complete(conv.ChatID, conv.State); err != nil || !ok {
+		return err
+	}
+	empID := h.targetID(c)
+	shiftID, err := h.Shifts.As(c.Sender().ID).AddShift(empID, date, amount)
+	if err != nil {
+		return c.Send(l.T("shift.add_failed", errText(l, err)))
+	}
+	h.notifyTarget(c, empID, func(l i18n.Lang) string {
+		return l.T("notify.shift_added", l.Date(date), l.Money(h.Shifts.EmployeeCurrency(empID), amount))
+	})
+	return c.Send(l.T("shift.added")+h.requestApproval(c, empID, shiftID), h.recordUndo(c, empID, domain.UndoShiftAdded, shiftID))
+}
 
-func (h *Handler) handleStart(c telebot.Context) error {
-    
-    if h.waitingAmount != nil {
-        delete(h.waitingAmount, c.Chat().ID)
-    }
-    if h.waitingPayout != nil {
-        delete(h.waitingPayout, c.Chat().ID)
-    }
-    
-    m := &telebot.ReplyMarkup{ResizeKeyboard: true}
-    btnAdd := m.Text("➕ Добавить смену")
-    btnSalary := m.Text("💰 Зарплата")
-    btnPayout := m.Text("💸 Выплата")
-    m.Reply(m.Row(btnAdd), m.Row(btnSalary, btnPayout))
-    return c.Send("Выберите действие:", m)
+func (h *Handler) handlePayoutAmount(c telebot.Context, conv domain.Conversation) error {
+	l := h.lang(c)
+	empID := h.targetID(c)
+	amount, currency, err := money.ParseWithCurrency(c.Text())
+	if errors.Is(err, money.ErrUnknownCurrency) {
+		return c.Send(l.T("payout.unknown_currency", currencyList()), cancelMarkupIn(l))
+	}
+	if err != nil {
+		return c.Send(l.T("shift.amount_invalid"), cancelMarkupIn(l))
+	}
+	if amount < money.FromMinor(100) {
+		return c.Send(l.T("payout.amount_min"), cancelMarkupIn(l))
+	}
+	unpaidTotal, err := h.Shifts.CalculateUnpaidSalary(empID)
+	if err != nil {
+		return c.Send(l.T("err.data", errText(l, err)))
+	}
+	currency = h.payoutCurrency(empID, unpaidTotal, currency)
+	if unpaidTotal[currency] == 0 && !unpaidTotal.IsZero() {
+		return c.Send(l.T("payout.no_currency", string(currency), l.Totals(unpaidTotal), string(unpaidTotal.Currencies()[0])), cancelMarkupIn(l))
+	}
+	if amount > unpaidTotal[currency] {
+		return c.Send(l.T("payout.too_much", l.Money(currency, unpaidTotal[currency])), cancelMarkupIn(l))
+	}
+	if ok, err := h.Conversations.Complete(conv.ChatID, conv.State); err != nil || !ok {
+		return err
+	}
+	payoutID, err := h.Shifts.As(c.Sender().ID).MarkShiftsPaidAmount(empID, amount, currency, c.Sender().ID, "")
+	if err != nil {
+		return c.Send(l.T("payout.failed", errText(l, err)))
+	}
+	note := h.requestPayoutConfirmation(l, payoutID)
+	if note == "" {
+		h.notifyTarget(c, empID, func(l i18n.Lang) string {
+			return l.T("notify.payout", l.Money(currency, amount))
+		})
+	}
+	return c.Send(l.T("payout.done", l.Money(currency, amount))+note, h.recordUndo(c, empID, domain.UndoPayout, payoutID))
+}
+
+// payoutCurrency выбирает валюту выплаты: указанную пользователем, иначе
+// единственную валюту долга, иначе валюту оплаты сотрудника.
+func (h *Handler) payoutCurrency(empID int, unpaid money.Totals, given money.Currency) money.Currency {
+	if given != "" {
+		return given
+	}
+	if cs := unpaid.Currencies(); len(cs) == 1 {
+		return cs[0]
+	}
+	return h.Shifts.EmployeeCurrency(empID)
+}
+
+// formatFor пишет сумму в валюте оплаты сотрудника.
+func (h *Handler) formatFor(l i18n.Lang, empID int, a money.Amount) string {
+	return l.Money(h.Shifts.EmployeeCurrency(empID), a)
+}
+
+func currencyList() string {
+	var codes []string
+	for _, c := range money.Currencies() {
+		codes = append(codes, string(c))
+	}
+	return strings.Join(codes, ", ")
+}
+
+func (h *Handler) RegisterHandlersCallback(c telebot.Context) error {
+	if h.Calendar != nil {
+		return h.Calendar.HandleCallback(c)
+	}
+	return nil
 }
 
+func (h *Handler) handleStart(c telebot.Context) error {
+	if h.Employees != nil {
+		me, err := h.registerSender(c)
+		if err != nil {
+			log.Printf("[employees] register sender=%d: %v", c.Sender().ID, err)
+		}
+		// пояс спрашивается, пока не задан: меню вернётся после ответа
+		if err == nil && me.TimeZone == "" {
+			return h.askTimeZone(c)
+		}
+	}
+	h.cancelFlow(c.Chat().ID)
+	l := h.lang(c)
+	return c.Send(l.T("menu.choose"), mainMenu(l))
+}
 
 func (h *Handler) handleEmployees(c telebot.Context) error {
-    return c.Send("Список сотрудников пока недоступен.")
+	me, err := h.registerSender(c)
+	l := h.lang(c)
+	if err != nil {
+		return c.Send(l.T("err.data", errText(l, err)))
+	}
+	employees, err := h.Employees.GetAllEmployees()
+	if err != nil {
+		return c.Send(l.T("err.employees", errText(l, err)))
+	}
+	if len(employees) == 0 {
+		return c.Send(l.T("employees.none"))
+	}
+	var b strings.Builder
+	b.WriteString(l.T("employees.header") + "\n")
+	markup := &telebot.ReplyMarkup{}
+	var rows []telebot.Row
+	for _, e := range employees {
+		unpaid, err := h.Shifts.CalculateUnpaidSalary(e.ID)
+		if err != nil {
+			return c.Send(l.T("err.salary", errText(l, err)))
+		}
+		last, err := h.Shifts.GetLastShiftDate(e.ID)
+		if err != nil {
+			return c.Send(l.T("err.shifts", errText(l, err)))
+		}
+		lastStr := l.T("employees.no_shifts")
+		if !last.IsZero() {
+			lastStr = l.T("employees.last_shift", l.Date(last))
+		}
+		b.WriteString(l.T("employees.line", employeeTitle(e), l.Totals(unpaid), lastStr) + "\n")
+		if e.ID != me.ID {
+			rows = append(rows, markup.Row(markup.Data("👤 "+e.Name, "act_as", strconv.Itoa(e.ID))))
+		}
+	}
+	if len(rows) == 0 {
+		return c.Send(b.String())
+	}
+	rows = append(rows, markup.Row(markup.Data(l.T("btn.my_data"), "act_self")))
+	markup.Inline(rows...)
+	b.WriteString("\n" + l.T("employees.hint"))
+	if target, onBehalf := h.target(c); onBehalf {
+		b.WriteString("\n" + l.T("employees.selected", employeeTitle(target)))
+	}
+	return c.Send(b.String(), markup)
 }
 
-// /resetme — удалить ВСЕ смены текущего пользователя (по его Telegram ID -> employeeID)
-func (h *Handler) handleResetMe(c telebot.Context) error {
-    empID := int(c.Sender().ID)
-    // Шаг 1: подтверждение
-    if len(c.Args()) == 0 {
-        m := &telebot.ReplyMarkup{}
-        yes := m.Data("✅ Да, удалить", "resetme_confirm")
-        no := m.Data("❌ Отмена", "cancel_flow")
-        m.Inline(m.Row(yes), m.Row(no))
-        return c.Send("Удалить все ваши смены и выплаты? Это действие необратимо.", m)
-    }
-    // Непосредственное подтверждение через аргумент, например: /resetme confirm
-    if len(c.Args()) > 0 && strings.EqualFold(c.Args()[0], "confirm") {
-        if err := h.Shifts.ResetEmployeeData(empID); err != nil {
-            return c.Send("Ошибка при сбросе данных: "+err.Error())
-        }
-        if h.waitingAmount != nil { delete(h.waitingAmount, c.Chat().ID) }
-        if h.waitingPayout != nil { delete(h.waitingPayout, c.Chat().ID) }
-        return c.Send("Ваши данные удалены.")
-    }
-    return c.Send("Чтобы подтвердить, нажмите кнопку или выполните: /resetme confirm")
+// registerSender регистрирует отправителя как сотрудника (или обновляет его данные).
+func (h *Handler) registerSender(c telebot.Context) (domain.Employee, error) {
+	u := c.Sender()
+	name := strings.TrimSpace(u.FirstName + " " + u.LastName)
+	if name == "" {
+		name = u.Username
+	}
+	return h.Employees.As(c.Sender().ID).Register(u.ID, c.Chat().ID, name, u.Username)
+}
+
+// resolveSender находит отправителя среди сотрудников, при первом
+// обращении регистрируя его.
+func (h *Handler) resolveSender(c telebot.Context) (domain.Employee, error) {
+	e, err := h.Employees.GetEmployeeByID(int(c.Sender().ID))
+	if errors.Is(err, domain.ErrEmployeeNotFound) {
+		return h.registerSender(c)
+	}
+	return e, err
 }
 
+func employeeTitle(e domain.Employee) string {
+	if e.Username != "" {
+		return e.Name + " (@" + e.Username + ")"
+	}
+	return e.Name
+}
 
+// /resetme — удалить ВСЕ смены текущего пользователя (по его Telegram ID -> employeeID)
+func (h *Handler) handleResetMe(c telebot.Context) error {
+	empID := int(c.Sender().ID)
+	l := h.lang(c)
+	// Шаг 1: подтверждение
+	if len(c.Args()) == 0 {
+		m := &telebot.ReplyMarkup{}
+		yes := m.Data(l.T("btn.reset_yes"), "resetme_confirm")
+		no := m.Data(l.T("btn.cancel"), "cancel_flow")
+		m.Inline(m.Row(yes), m.Row(no))
+		return c.Send(l.T("reset.ask"), m)
+	}
+	// Непосредственное подтверждение через аргумент, например: /resetme confirm
+	if len(c.Args()) > 0 && strings.EqualFold(c.Args()[0], "confirm") {
+		if err := h.Shifts.As(c.Sender().ID).ResetEmployeeData(empID); err != nil {
+			return c.Send(l.T("reset.failed", errText(l, err)))
+		}
+		h.cancelFlow(c.Chat().ID)
+		return c.Send(l.T("reset.done"))
+	}
+	return c.Send(l.T("reset.hint"))
+}
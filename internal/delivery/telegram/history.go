@@ -0,0 +1,131 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/i18n"
+	"salary-bot/pkg/money"
+
+	"gopkg.in/telebot.v3"
+)
+
+const historyLimit = 15
+
+// /history — последние изменения, сделанные пользователем или касающиеся его.
+func (h *Handler) handleHistory(c telebot.Context) error {
+	l := h.lang(c)
+	entries, err := h.Audit.History(c.Sender().ID, historyLimit)
+	if err != nil {
+		return c.Send(l.T("err.history", errText(l, err)))
+	}
+	if len(entries) == 0 {
+		return c.Send(l.T("history.none"))
+	}
+	loc := h.location(c)
+	var b strings.Builder
+	b.WriteString(l.T("history.header") + "\n")
+	for _, e := range entries {
+		at := e.CreatedAt.In(loc)
+		b.WriteString(l.DayMonth(at) + " " + at.Format("15:04") + " — " + describeAudit(l, e))
+		if e.ActorID != c.Sender().ID {
+			b.WriteString(" (" + h.actorName(e.ActorID) + ")")
+		}
+		b.WriteString("\n")
+	}
+	return c.Send(b.String())
+}
+
+func (h *Handler) actorName(actorID int64) string {
+	if e, err := h.Employees.GetEmployeeByID(int(actorID)); err == nil && e.Name != "" {
+		return e.Name
+	}
+	return fmt.Sprintf("id %d", actorID)
+}
+
+// auditShift и auditPayout — поля снимков, нужные для описания записи.
+type auditShift struct {
+	Date     time.Time
+	Amount   money.Amount
+	Status   string
+	Currency money.Currency
+}
+
+type auditPayout struct {
+	Payout struct {
+		Amount   money.Amount
+		Status   string
+		Currency money.Currency
+	}
+}
+
+type auditRateCard struct {
+	Amount        money.Amount
+	EffectiveFrom time.Time
+}
+
+func describeAudit(l i18n.Lang, e domain.AuditEntry) string {
+	switch e.Entity {
+	case domain.EntityShift:
+		var before, after auditShift
+		_ = json.Unmarshal([]byte(e.Before), &before)
+		_ = json.Unmarshal([]byte(e.After), &after)
+		switch e.Action {
+		case domain.AuditCreate:
+			return l.T("audit.shift_created", l.Date(after.Date), l.Money(after.Currency, after.Amount))
+		case domain.AuditUpdate:
+			var changes []string
+			if !before.Date.Equal(after.Date) {
+				changes = append(changes, l.T("audit.date_changed", l.Date(before.Date), l.Date(after.Date)))
+			}
+			if before.Amount != after.Amount {
+				changes = append(changes, l.T("audit.amount_changed", l.Money(before.Currency, before.Amount), l.Money(after.Currency, after.Amount)))
+			}
+			if before.Status != after.Status {
+				switch after.Status {
+				case domain.ShiftApproved:
+					changes = append(changes, l.T("audit.approved"))
+				case domain.ShiftRejected:
+					changes = append(changes, l.T("audit.rejected"))
+				case domain.ShiftPending:
+					changes = append(changes, l.T("audit.pending"))
+				}
+			}
+			return l.T("audit.shift_updated", l.Date(before.Date), strings.Join(changes, ", "))
+		case domain.AuditDelete:
+			return l.T("audit.shift_deleted", l.Date(before.Date), l.Money(before.Currency, before.Amount))
+		case domain.AuditDeleteAll:
+			return l.T("audit.shifts_deleted")
+		}
+	case domain.EntityPayout:
+		var before, after auditPayout
+		_ = json.Unmarshal([]byte(e.Before), &before)
+		_ = json.Unmarshal([]byte(e.After), &after)
+		switch e.Action {
+		case domain.AuditCreate:
+			return l.T("audit.payout_created", e.EntityID, l.Money(after.Payout.Currency, after.Payout.Amount))
+		case domain.AuditUpdate:
+			if after.Payout.Status == domain.PayoutDisputed {
+				return l.T("audit.payout_disputed", e.EntityID, l.Money(after.Payout.Currency, after.Payout.Amount))
+			}
+			return l.T("audit.payout_confirmed", e.EntityID, l.Money(after.Payout.Currency, after.Payout.Amount))
+		case domain.AuditDelete:
+			return l.T("audit.payout_deleted", e.EntityID, l.Money(before.Payout.Currency, before.Payout.Amount))
+		case domain.AuditDeleteAll:
+			return l.T("audit.payouts_deleted")
+		}
+	case domain.EntityEmployee:
+		if e.Action == domain.AuditCreate {
+			return l.T("audit.employee_created")
+		}
+		return l.T("audit.employee_updated")
+	case domain.EntityRateCard:
+		var after auditRateCard
+		_ = json.Unmarshal([]byte(e.After), &after)
+		return l.T("audit.rate", l.Number(after.Amount), l.Date(after.EffectiveFrom))
+	}
+	return e.Action + " " + e.Entity
+}
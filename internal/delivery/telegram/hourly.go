@@ -0,0 +1,131 @@
+package telegram
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/i18n"
+	"salary-bot/pkg/money"
+
+	"gopkg.in/telebot.v3"
+)
+
+var errBadShiftTimes = errors.New("bad shift times")
+
+// /hourly 350 — перейти на почасовую оплату со ставкой 350 в час,
+// /hourly off — вернуться к оплате за смену.
+func (h *Handler) handleHourly(c telebot.Context) error {
+	me, err := h.registerSender(c)
+	l := h.lang(c)
+	if err != nil {
+		return c.Send(l.T("err.data", errText(l, err)))
+	}
+	if len(c.Args()) == 0 {
+		if me.IsHourly() {
+			return c.Send(l.T("hourly.current", l.Money(me.Currency, me.HourlyRate)))
+		}
+		return c.Send(l.T("hourly.per_shift"))
+	}
+	if strings.EqualFold(c.Args()[0], "off") {
+		if _, err := h.Employees.As(c.Sender().ID).SetPayType(me.ID, domain.PayPerShift, 0); err != nil {
+			return c.Send(l.T("err.generic", errText(l, err)))
+		}
+		return c.Send(l.T("hourly.disabled"))
+	}
+	rate, err := money.Parse(c.Args()[0])
+	if err != nil || rate <= 0 {
+		return c.Send(l.T("hourly.invalid"))
+	}
+	if _, err := h.Employees.As(c.Sender().ID).SetPayType(me.ID, domain.PayHourly, rate); err != nil {
+		return c.Send(l.T("err.generic", errText(l, err)))
+	}
+	return c.Send(l.T("hourly.enabled", l.Money(me.Currency, rate)))
+}
+
+func (h *Handler) askShiftTimes(c telebot.Context, date time.Time) error {
+	l := h.lang(c)
+	err := h.Conversations.Set(c.Chat().ID, domain.StateAwaitShiftTimes, map[string]string{
+		"date": date.Format("2006-01-02"),
+	})
+	if err != nil {
+		return c.Send(l.T("err.generic", errText(l, err)))
+	}
+	text := l.T("hourly.ask_times", l.Date(date))
+	if err := c.Edit(text, cancelMarkupIn(l)); err != nil {
+		_ = c.Send(text, cancelMarkupIn(l))
+	}
+	return nil
+}
+
+func (h *Handler) handleShiftTimes(c telebot.Context, conv domain.Conversation) error {
+	l := h.lang(c)
+	date, err := time.Parse("2006-01-02", conv.Data["date"])
+	if err != nil {
+		h.cancelFlow(conv.ChatID)
+		return c.Send(l.T("err.date"))
+	}
+	hourly, err := parseShiftTimes(c.Text())
+	if err != nil {
+		return c.Send(l.T("hourly.times_invalid"), cancelMarkupIn(l))
+	}
+	me, _ := h.target(c)
+	rate, ok := h.shiftRate(c, date)
+	if !ok {
+		return c.Send(l.T("hourly.no_rate"), cancelMarkupIn(l))
+	}
+	hourly.Rate = rate
+	if hourly.Worked() <= 0 {
+		return c.Send(l.T("hourly.break_too_long"), cancelMarkupIn(l))
+	}
+	if ok, err := h.Conversations.Complete(conv.ChatID, conv.State); err != nil || !ok {
+		return err
+	}
+	shiftID, amount, err := h.Shifts.As(c.Sender().ID).AddHourlyShift(me.ID, date, hourly)
+	if err != nil {
+		return c.Send(l.T("shift.add_failed", errText(l, err)))
+	}
+	h.notifyTarget(c, me.ID, func(l i18n.Lang) string {
+		return l.T("notify.shift_added", l.Date(date), l.Money(me.Currency, amount))
+	})
+	return c.Send(l.T("hourly.added", formatWorked(l, hourly.Worked()), l.Money(me.Currency, hourly.Rate), l.Money(me.Currency, amount))+h.requestApproval(c, me.ID, shiftID),
+		h.recordUndo(c, me.ID, domain.UndoShiftAdded, shiftID))
+}
+
+// parseShiftTimes разбирает "ЧЧ:ММ-ЧЧ:ММ [перерыв в минутах]".
+func parseShiftTimes(s string) (domain.HourlyShift, error) {
+	var hs domain.HourlyShift
+	fields := strings.Fields(strings.ReplaceAll(s, "—", "-"))
+	if len(fields) == 0 || len(fields) > 2 {
+		return hs, errBadShiftTimes
+	}
+	startStr, endStr, ok := strings.Cut(fields[0], "-")
+	if !ok {
+		return hs, errBadShiftTimes
+	}
+	var err error
+	if hs.Start, err = domain.ParseClock(strings.TrimSpace(startStr)); err != nil {
+		return hs, errBadShiftTimes
+	}
+	if hs.End, err = domain.ParseClock(strings.TrimSpace(endStr)); err != nil {
+		return hs, errBadShiftTimes
+	}
+	if len(fields) == 2 {
+		hs.BreakMinutes, err = strconv.Atoi(fields[1])
+		if err != nil || hs.BreakMinutes < 0 {
+			return hs, errBadShiftTimes
+		}
+	}
+	return hs, nil
+}
+
+func formatWorked(l i18n.Lang, d time.Duration) string {
+	hours := int(d / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	if minutes == 0 {
+		return l.T("worked.hours", hours)
+	}
+	return l.T("worked.hours_minutes", hours, minutes)
+}
@@ -0,0 +1,114 @@
+package telegram
+
+import (
+	"io"
+	"log"
+	"strings"
+
+	"salary-bot/internal/app/service"
+	"salary-bot/internal/delivery/telegram/middleware"
+	"salary-bot/internal/delivery/telegram/router"
+	"salary-bot/internal/domain"
+
+	"gopkg.in/telebot.v3"
+)
+
+const (
+	maxImportFileSize = 1 << 20
+	// importPreviewLines — сколько ошибок и дубликатов показывать в превью.
+	importPreviewLines = 10
+)
+
+func (h *Handler) handleImport(c telebot.Context) error {
+	h.cancelFlow(c.Chat().ID)
+	return c.Send(h.lang(c).T("import.help"))
+}
+
+func (h *Handler) handleDocument(c telebot.Context) error {
+	l := h.lang(c)
+	doc := c.Message().Document
+	if doc == nil || !strings.HasSuffix(strings.ToLower(doc.FileName), ".csv") {
+		return c.Send(l.T("import.only_csv"))
+	}
+	if doc.FileSize > maxImportFileSize {
+		return c.Send(l.T("import.too_big"))
+	}
+	h.cancelFlow(c.Chat().ID)
+
+	rc, err := h.Bot.File(&doc.File)
+	if err != nil {
+		log.Printf("[import] download chat=%d: %v", c.Chat().ID, err)
+		return c.Send(l.T("import.download_failed", errText(l, err)))
+	}
+	defer rc.Close()
+
+	preview, err := h.Shifts.PreviewImport(int(c.Sender().ID), io.LimitReader(rc, maxImportFileSize))
+	if err != nil {
+		return c.Send(l.T("import.parse_failed", errText(l, err)))
+	}
+
+	var b strings.Builder
+	b.WriteString(l.T("import.header") + "\n")
+	b.WriteString(l.T("import.valid", len(preview.Valid)) + "\n")
+	b.WriteString(l.T("import.duplicates", len(preview.Duplicates)) + "\n")
+	b.WriteString(l.T("import.errors", len(preview.Errors)) + "\n")
+	for i, e := range preview.Errors {
+		if i == importPreviewLines {
+			b.WriteString("…\n")
+			break
+		}
+		b.WriteString(l.T("import.line_error", e.Line, l.T(e.Key, e.Args...)) + "\n")
+	}
+	for i, d := range preview.Duplicates {
+		if i == importPreviewLines {
+			b.WriteString("…\n")
+			break
+		}
+		cur := d.Currency
+		if cur == "" {
+			cur = h.Shifts.EmployeeCurrency(int(c.Sender().ID))
+		}
+		b.WriteString(l.T("import.line_duplicate", d.Line, l.Date(d.Date), l.Money(cur, d.Amount)) + "\n")
+	}
+
+	if len(preview.Valid) == 0 {
+		b.WriteString("\n" + l.T("import.nothing"))
+		return c.Send(b.String())
+	}
+	if err := h.Conversations.Set(c.Chat().ID, domain.StateConfirmImport, map[string]string{
+		"rows": service.EncodeImportRows(preview.Valid),
+	}); err != nil {
+		return c.Send(l.T("err.generic", errText(l, err)))
+	}
+	markup := &telebot.ReplyMarkup{}
+	btnOK := markup.Data(l.T("import.confirm", l.N("shifts", len(preview.Valid))), "import_confirm")
+	btnCancel := markup.Data(l.T("btn.cancel"), "cancel_flow")
+	markup.Inline(markup.Row(btnOK), markup.Row(btnCancel))
+	return c.Send(b.String(), markup)
+}
+
+func (h *Handler) registerImport(r *router.CallbackRouter) {
+	r.Register("import_confirm", func(c telebot.Context, payload string) error {
+		l := h.lang(c)
+		conv, err := h.Conversations.Get(c.Chat().ID)
+		if err != nil {
+			return c.Send(l.T("err.generic", errText(l, err)))
+		}
+		if conv.State != domain.StateConfirmImport {
+			return middleware.EditOrSend(c, l.T("import.stale"), nil)
+		}
+		if ok, err := h.Conversations.Complete(conv.ChatID, conv.State); err != nil || !ok {
+			return err
+		}
+		rows, err := service.DecodeImportRows(conv.Data["rows"])
+		if err != nil {
+			return c.Send(l.T("import.failed", errText(l, err)))
+		}
+		n, err := h.Shifts.As(c.Sender().ID).ImportShifts(int(c.Sender().ID), rows)
+		if err != nil {
+			log.Printf("[import] commit chat=%d: %v", c.Chat().ID, err)
+			return middleware.EditOrSend(c, l.T("import.rolled_back", errText(l, err)), nil)
+		}
+		return middleware.EditOrSend(c, l.T("import.done", l.N("shifts", n))+h.requestImportApproval(c, int(c.Sender().ID), n), nil)
+	})
+}
@@ -3,28 +3,55 @@ package keyboards
 import (
 	"fmt"
 	"strconv"
+	"time"
+
+	"salary-bot/pkg/i18n"
 
 	"gopkg.in/telebot.v3"
 )
 
-
-func BuildMonthKeyboard(year int) (string, *telebot.ReplyMarkup) {
+func BuildMonthKeyboard(lang i18n.Lang, year int) (string, *telebot.ReplyMarkup) {
 	markup := &telebot.ReplyMarkup{}
-	
-	monthNames := []string{"Янв", "Фев", "Мар", "Апр", "Май", "Июн", "Июл", "Авг", "Сен", "Окт", "Ноя", "Дек"}
-	rows := []telebot.Row{}
-	for i := 0; i < 12; i += 3 {
-		b1 := markup.Data(monthNames[i], "pick_month", fmt.Sprintf("%04d-%02d", year, i+1))
-		b2 := markup.Data(monthNames[i+1], "pick_month", fmt.Sprintf("%04d-%02d", year, i+2))
-		b3 := markup.Data(monthNames[i+2], "pick_month", fmt.Sprintf("%04d-%02d", year, i+3))
-		rows = append(rows, markup.Row(b1, b2, b3))
-	}
-	
+	rows := monthRows(markup, lang, year, "pick_month")
+
 	prev := markup.Data("← "+strconv.Itoa(year-1), "month_prev", strconv.Itoa(year))
 	next := markup.Data(strconv.Itoa(year+1)+" →", "month_next", strconv.Itoa(year))
 	rows = append(rows, markup.Row(prev, next))
-	
+
+	markup.Inline(rows...)
+	return lang.T("month.pick", year), markup
+}
+
+// BuildMonthKeyboardFor строит ту же клавиатуру для другого сценария:
+// месяц выбирается кнопкой pickKey (payload ГГГГ-ММ), а год листается
+// кнопкой yearKey, payload которой — год, который нужно показать.
+func BuildMonthKeyboardFor(lang i18n.Lang, year int, pickKey, yearKey string) (string, *telebot.ReplyMarkup) {
+	markup := &telebot.ReplyMarkup{}
+	rows := monthRows(markup, lang, year, pickKey)
+
+	prev := markup.Data("← "+strconv.Itoa(year-1), yearKey, strconv.Itoa(year-1))
+	next := markup.Data(strconv.Itoa(year+1)+" →", yearKey, strconv.Itoa(year+1))
+	rows = append(rows, markup.Row(prev, next))
+
 	markup.Inline(rows...)
-	title := fmt.Sprintf("Выберите месяц: %d", year)
-	return title, markup
+	return lang.T("month.pick", year), markup
+}
+
+func monthRows(markup *telebot.ReplyMarkup, lang i18n.Lang, year int, pickKey string) []telebot.Row {
+	rows := []telebot.Row{}
+	for m := time.January; m <= time.December; m += 3 {
+		b1 := markup.Data(lang.MonthShort(m), pickKey, fmt.Sprintf("%04d-%02d", year, m))
+		b2 := markup.Data(lang.MonthShort(m+1), pickKey, fmt.Sprintf("%04d-%02d", year, m+1))
+		b3 := markup.Data(lang.MonthShort(m+2), pickKey, fmt.Sprintf("%04d-%02d", year, m+2))
+		rows = append(rows, markup.Row(b1, b2, b3))
+	}
+	return rows
+}
+
+// ParseMonth разбирает payload ГГГГ-ММ кнопки выбора месяца.
+func ParseMonth(payload string) (year, month int, ok bool) {
+	if _, err := fmt.Sscanf(payload, "%04d-%02d", &year, &month); err != nil {
+		return 0, 0, false
+	}
+	return year, month, month >= 1 && month <= 12
 }
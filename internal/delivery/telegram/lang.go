@@ -0,0 +1,123 @@
+package telegram
+
+import (
+	"errors"
+	"strings"
+
+	"salary-bot/internal/app/service"
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/i18n"
+	"salary-bot/pkg/money"
+
+	"gopkg.in/telebot.v3"
+)
+
+// lang — язык сообщений отправителю: выбранный через /lang, иначе язык
+// Telegram, иначе Locale.
+func (h *Handler) lang(c telebot.Context) i18n.Lang {
+	if c.Sender() == nil {
+		return h.Locale.OrDefault()
+	}
+	if e, err := h.Employees.GetEmployeeByID(int(c.Sender().ID)); err == nil && e.Language != "" {
+		return i18n.Lang(e.Language).OrDefault()
+	}
+	return i18n.Match(c.Sender().LanguageCode, h.Locale)
+}
+
+// langOf — язык сообщений, которые бот сам отправляет сотруднику e. Язык
+// Telegram здесь неизвестен, поэтому без /lang используется Locale.
+func (h *Handler) langOf(e domain.Employee) i18n.Lang {
+	if e.Language != "" {
+		return i18n.Lang(e.Language).OrDefault()
+	}
+	return h.Locale.OrDefault()
+}
+
+// errorKeys — сообщения каталога для ошибок сервисов: текст самих ошибок
+// пишется для журнала и не переводится.
+var errorKeys = []struct {
+	err  error
+	key  string
+	args []any
+}{
+	{domain.ErrShiftNotFound, "error.shift_not_found", nil},
+	{domain.ErrPayoutNotFound, "error.payout_not_found", nil},
+	{domain.ErrPayoutExceedsBalance, "error.payout_exceeds_balance", nil},
+	{domain.ErrEmployeeNotFound, "error.employee_not_found", nil},
+	{domain.ErrInvalidTimeZone, "error.invalid_time_zone", nil},
+	{service.ErrNotManager, "error.not_manager", nil},
+	{service.ErrCurrencyMismatch, "error.currency_mismatch", nil},
+	{service.ErrShiftPaid, "error.shift_paid", nil},
+	{service.ErrShiftReviewed, "error.shift_reviewed", nil},
+	{service.ErrPayoutReviewed, "error.payout_reviewed", nil},
+	{service.ErrNotPayoutCounterpart, "error.not_payout_counterpart", nil},
+	{service.ErrPayoutConfirmed, "error.payout_confirmed", nil},
+	{service.ErrShiftApproved, "error.shift_approved", nil},
+	{service.ErrResetLocked, "error.reset_locked", nil},
+	{service.ErrImportEmpty, "error.import_empty", nil},
+	{service.ErrImportTooBig, "error.import_too_big", []any{service.MaxImportRows}},
+	{service.ErrImportColumns, "error.import_columns", nil},
+	{service.ErrNothingToUndo, "error.nothing_to_undo", nil},
+	{service.ErrUndoExpired, "error.undo_expired", nil},
+	{service.ErrUndoNotLatest, "error.undo_not_latest", nil},
+	{service.ErrUnknownFormat, "error.unknown_format", nil},
+	{money.ErrUnknownCurrency, "error.unknown_currency", nil},
+	{money.ErrInvalidAmount, "error.invalid_amount", nil},
+	{i18n.ErrUnknownLang, "error.unknown_lang", nil},
+}
+
+// errText — текст ошибки err на языке l для подстановки в сообщение.
+// Ошибки, которых нет в errorKeys (базы данных, сети), показываются как есть.
+func errText(l i18n.Lang, err error) string {
+	for _, e := range errorKeys {
+		if errors.Is(err, e.err) {
+			return l.T(e.key, e.args...)
+		}
+	}
+	return err.Error()
+}
+
+func mainMenu(l i18n.Lang) *telebot.ReplyMarkup {
+	m := &telebot.ReplyMarkup{ResizeKeyboard: true}
+	btnAdd := m.Text(l.T("menu.add_shift"))
+	btnSalary := m.Text(l.T("menu.salary"))
+	btnPayout := m.Text(l.T("menu.payout"))
+	m.Reply(m.Row(btnAdd), m.Row(btnSalary, btnPayout))
+	return m
+}
+
+// /lang en — язык сообщений, /lang auto — как в Telegram.
+func (h *Handler) handleLang(c telebot.Context) error {
+	me, err := h.registerSender(c)
+	l := h.lang(c)
+	if err != nil {
+		return c.Send(l.T("err.data", errText(l, err)))
+	}
+	if len(c.Args()) == 0 {
+		if me.Language == "" {
+			return c.Send(l.T("lang.auto", l.Name()))
+		}
+		return c.Send(l.T("lang.current", l.Name()))
+	}
+	code := ""
+	if !strings.EqualFold(c.Args()[0], "auto") {
+		chosen, err := i18n.Parse(c.Args()[0])
+		if err != nil {
+			var codes []string
+			for _, lang := range i18n.Langs() {
+				codes = append(codes, string(lang))
+			}
+			return c.Send(l.T("lang.unknown", strings.Join(codes, ", ")))
+		}
+		code = string(chosen)
+	}
+	if _, err := h.Employees.As(c.Sender().ID).SetLanguage(me.ID, code); err != nil {
+		return c.Send(l.T("err.save", errText(l, err)))
+	}
+	// клавиатура меню пересылается, чтобы кнопки были на новом языке
+	l = h.lang(c)
+	if code == "" {
+		return c.Send(l.T("lang.set_auto", l.Name()), mainMenu(l))
+	}
+	return c.Send(l.T("lang.set", l.Name()), mainMenu(l))
+}
@@ -0,0 +1,25 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"salary-bot/pkg/i18n"
+)
+
+// TestErrorKeys проверяет, что у каждой ошибки сервиса есть сообщение в
+// каталоге и аргументы подходят к его подстановкам.
+func TestErrorKeys(t *testing.T) {
+	for _, e := range errorKeys {
+		if !i18n.Has(e.key) {
+			t.Errorf("%v: no message %q", e.err, e.key)
+			continue
+		}
+		for _, l := range i18n.Langs() {
+			if msg := errText(l, fmt.Errorf("wrapped: %w", e.err)); strings.Contains(msg, "%!") {
+				t.Errorf("%s %q = %q", l, e.key, msg)
+			}
+		}
+	}
+}
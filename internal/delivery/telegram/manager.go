@@ -0,0 +1,84 @@
+package telegram
+
+import (
+	"log"
+	"strconv"
+
+	"salary-bot/internal/delivery/telegram/middleware"
+	"salary-bot/internal/delivery/telegram/router"
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/i18n"
+
+	"gopkg.in/telebot.v3"
+)
+
+// target возвращает сотрудника, над данными которого работают сценарии
+// добавления смены, зарплаты и выплаты. onBehalf == true, если это
+// менеджер, выбравший другого сотрудника.
+func (h *Handler) target(c telebot.Context) (e domain.Employee, onBehalf bool) {
+	e, err := h.Employees.Target(c.Sender().ID)
+	if err != nil {
+		log.Printf("[manager] target sender=%d: %v", c.Sender().ID, err)
+		return domain.Employee{ID: int(c.Sender().ID)}, false
+	}
+	return e, int64(e.ID) != c.Sender().ID
+}
+
+func (h *Handler) targetID(c telebot.Context) int {
+	e, _ := h.target(c)
+	return e.ID
+}
+
+// targetHeader — строка-напоминание менеджеру, чьи данные он сейчас видит.
+func (h *Handler) targetHeader(c telebot.Context) string {
+	e, onBehalf := h.target(c)
+	if !onBehalf {
+		return ""
+	}
+	return "👤 " + employeeTitle(e) + "\n"
+}
+
+// notifyTarget сообщает сотруднику об изменении, сделанном менеджером
+// от его имени; text собирается на языке сотрудника. Свои действия
+// сотрудник не получает.
+func (h *Handler) notifyTarget(c telebot.Context, employeeID int, text func(i18n.Lang) string) {
+	if int64(employeeID) == c.Sender().ID {
+		return
+	}
+	e, err := h.Employees.GetEmployeeByID(employeeID)
+	if err != nil || e.ChatID == 0 {
+		return
+	}
+	l := h.langOf(e)
+	if _, err := h.Bot.Send(telebot.ChatID(e.ChatID), l.T("notify.by_manager", h.actorName(c.Sender().ID), text(l))); err != nil {
+		log.Printf("[manager] notify employee=%d: %v", employeeID, err)
+	}
+}
+
+func (h *Handler) registerManager(r *router.CallbackRouter) {
+	r.Register("act_as", func(c telebot.Context, payload string) error {
+		id, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil
+		}
+		h.cancelFlow(c.Chat().ID)
+		l := h.lang(c)
+		e, err := h.Employees.ActFor(c.Sender().ID, id)
+		if err != nil {
+			return c.Send(l.T("act.failed", errText(l, err)))
+		}
+		if int64(e.ID) == c.Sender().ID {
+			return middleware.EditOrSend(c, l.T("act.self"), nil)
+		}
+		return middleware.EditOrSend(c, l.T("act.other", l.T("menu.add_shift"), l.T("menu.salary"), l.T("menu.payout"),
+			employeeTitle(e), l.T("btn.my_data")), nil)
+	})
+	r.Register("act_self", func(c telebot.Context, payload string) error {
+		h.cancelFlow(c.Chat().ID)
+		l := h.lang(c)
+		if err := h.Employees.StopActing(c.Sender().ID); err != nil {
+			return c.Send(l.T("err.generic", errText(l, err)))
+		}
+		return middleware.EditOrSend(c, l.T("act.self"), nil)
+	})
+}
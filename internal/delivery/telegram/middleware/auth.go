@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"log"
+	"strings"
+
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/i18n"
+
+	"gopkg.in/telebot.v3"
+)
+
+// Access — уровень доступа к команде или callback.
+type Access int
+
+const (
+	// AccessEmployee — любой сотрудник. Новый пользователь из allowlist
+	// регистрируется при первом обращении.
+	AccessEmployee Access = iota
+	// AccessManager — только сотрудник с ролью менеджера.
+	AccessManager
+)
+
+// Auth — матрица доступа к командам и callback-ключам. Команды без записи
+// и обычные сообщения доступны сотрудникам; неизвестные callback-ключи
+// запрещены, чтобы поддельные кнопки не доходили до обработчиков.
+type Auth struct {
+	// Resolve находит (или регистрирует) сотрудника по отправителю.
+	Resolve func(c telebot.Context) (domain.Employee, error)
+	// Allowed — allowlist Telegram ID; пустой — бот открыт всем.
+	Allowed map[int64]bool
+	// Lang — язык отказа; nil — язык по умолчанию.
+	Lang      func(c telebot.Context) i18n.Lang
+	commands  map[string]Access
+	callbacks map[string]Access
+}
+
+func NewAuth(resolve func(c telebot.Context) (domain.Employee, error), allowed map[int64]bool) *Auth {
+	return &Auth{
+		Resolve:   resolve,
+		Allowed:   allowed,
+		commands:  make(map[string]Access),
+		callbacks: make(map[string]Access),
+	}
+}
+
+func (a *Auth) Command(cmd string, access Access) {
+	a.commands[cmd] = access
+}
+
+func (a *Auth) Callback(key string, access Access) {
+	a.callbacks[key] = access
+}
+
+// Middleware подключается через bot.Use до регистрации обработчиков.
+func (a *Auth) Middleware(next telebot.HandlerFunc) telebot.HandlerFunc {
+	return func(c telebot.Context) error {
+		sender := c.Sender()
+		if sender == nil {
+			return nil
+		}
+		if len(a.Allowed) > 0 && !a.Allowed[sender.ID] {
+			log.Printf("[auth] sender=%d not in allowlist", sender.ID)
+			return a.deny(c)
+		}
+		access, ok := a.required(c)
+		if !ok {
+			log.Printf("[auth] sender=%d unknown callback %q", sender.ID, c.Data())
+			return a.deny(c)
+		}
+		e, err := a.Resolve(c)
+		if err != nil {
+			log.Printf("[auth] resolve sender=%d: %v", sender.ID, err)
+			return a.deny(c)
+		}
+		if access == AccessManager && !e.IsManager() {
+			return a.deny(c)
+		}
+		return next(c)
+	}
+}
+
+// required возвращает уровень доступа для обновления; ok == false —
+// callback с неизвестным ключом.
+func (a *Auth) required(c telebot.Context) (Access, bool) {
+	if cb := c.Callback(); cb != nil {
+		key := strings.TrimPrefix(cb.Data, "\f")
+		if i := strings.IndexByte(key, '|'); i >= 0 {
+			key = key[:i]
+		}
+		if strings.HasPrefix(key, "cal_") {
+			return AccessEmployee, true
+		}
+		access, ok := a.callbacks[key]
+		return access, ok
+	}
+	if msg := c.Message(); msg != nil && strings.HasPrefix(msg.Text, "/") {
+		cmd, _, _ := strings.Cut(strings.Fields(msg.Text)[0], "@")
+		return a.commands[cmd], true
+	}
+	return AccessEmployee, true
+}
+
+func (a *Auth) deny(c telebot.Context) error {
+	l := i18n.Default
+	if a.Lang != nil {
+		l = a.Lang(c)
+	}
+	text := l.T("auth.denied")
+	if c.Callback() != nil {
+		return c.Respond(&telebot.CallbackResponse{Text: text, ShowAlert: true})
+	}
+	return c.Send(text)
+}
@@ -0,0 +1,142 @@
+package telegram
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"salary-bot/internal/app/service"
+	"salary-bot/internal/delivery/telegram/middleware"
+	"salary-bot/internal/delivery/telegram/router"
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/i18n"
+
+	"gopkg.in/telebot.v3"
+)
+
+// requestPayoutConfirmation просит вторую сторону подтвердить выплату и
+// возвращает пометку на языке l для ответа записавшему. Для подтверждённой
+// выплаты возвращается пустая строка.
+func (h *Handler) requestPayoutConfirmation(l i18n.Lang, payoutID int) string {
+	p, err := h.Shifts.GetPayout(payoutID)
+	if err != nil || p.Status != domain.PayoutUnconfirmed {
+		return ""
+	}
+	h.sendPayoutPrompt(p, false)
+	if p.RecordedByEmployee() {
+		return "\n" + l.T("payout.awaits_manager")
+	}
+	return "\n" + l.T("payout.awaits_employee")
+}
+
+// sendPayoutPrompt отправляет кнопки «получено / не получено» стороне,
+// которая подтверждает выплату; reminder — повторный запрос.
+func (h *Handler) sendPayoutPrompt(p domain.Payout, reminder bool) {
+	id := strconv.Itoa(p.ID)
+	prefix := func(l i18n.Lang) string {
+		if reminder {
+			return l.T("payout.reminder")
+		}
+		return ""
+	}
+	if !p.RecordedByEmployee() {
+		e, err := h.Employees.GetEmployeeByID(p.EmployeeID)
+		if err != nil || e.ChatID == 0 {
+			return
+		}
+		l := h.langOf(e)
+		m := &telebot.ReplyMarkup{}
+		m.Inline(m.Row(m.Data(l.T("payout.btn_received"), "payout_received", id), m.Data(l.T("payout.btn_not_received"), "payout_not_received", id)))
+		text := prefix(l) + l.T("payout.ask_employee", h.actorName(p.RecordedBy), p.ID, l.Date(p.Date), l.Money(p.Currency, p.Amount))
+		if _, err := h.Bot.Send(telebot.ChatID(e.ChatID), text, m); err != nil {
+			log.Printf("[payout] prompt employee=%d: %v", e.ID, err)
+		}
+		return
+	}
+	name := h.employeeName(p.EmployeeID)
+	h.sendManagers(p.RecordedBy, func(l i18n.Lang) (string, *telebot.ReplyMarkup) {
+		m := &telebot.ReplyMarkup{}
+		m.Inline(m.Row(m.Data(l.T("payout.btn_paid"), "payout_received", id), m.Data(l.T("payout.btn_not_paid"), "payout_not_received", id)))
+		return prefix(l) + l.T("payout.ask_manager", name, p.ID, l.Date(p.Date), l.Money(p.Currency, p.Amount)), m
+	})
+}
+
+// RunPayoutEscalations раз в interval напоминает о выплатах, не
+// подтверждённых дольше delay. Блокирует вызывающего.
+func (h *Handler) RunPayoutEscalations(interval, delay time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		h.escalatePayouts(now, delay)
+	}
+}
+
+func (h *Handler) escalatePayouts(now time.Time, delay time.Duration) {
+	payouts, err := h.Shifts.EscalatePayouts(now, delay)
+	if err != nil {
+		log.Printf("[payout] escalate: %v", err)
+	}
+	for _, p := range payouts {
+		h.sendPayoutPrompt(p, true)
+		if p.RecordedByEmployee() {
+			continue
+		}
+		// сотрудник не ответил — сообщаем и менеджерам
+		name := h.employeeName(p.EmployeeID)
+		h.sendManagers(0, func(l i18n.Lang) (string, *telebot.ReplyMarkup) {
+			return l.T("payout.not_confirmed", name, p.ID, l.Date(p.Date), l.Money(p.Currency, p.Amount)), nil
+		})
+	}
+}
+
+func (h *Handler) registerPayoutConfirm(r *router.CallbackRouter) {
+	answer := func(received bool) router.HandlerFunc {
+		return func(c telebot.Context, payload string) error {
+			id, err := strconv.Atoi(payload)
+			if err != nil {
+				return nil
+			}
+			l := h.lang(c)
+			p, err := h.Shifts.As(c.Sender().ID).ConfirmPayout(id, received)
+			switch {
+			case errors.Is(err, service.ErrPayoutReviewed), errors.Is(err, domain.ErrPayoutNotFound):
+				return middleware.EditOrSend(c, l.T("payout.already_reviewed"), nil)
+			case err != nil:
+				return c.Send(l.T("err.generic", errText(l, err)))
+			}
+			h.notifyPayoutRecorder(c, p, received)
+			text := payoutDecision(l, p, received)
+			if p.RecordedByEmployee() {
+				text = "👤 " + h.employeeName(p.EmployeeID) + "\n" + text
+			}
+			return middleware.EditOrSend(c, text, nil)
+		}
+	}
+	r.Register("payout_received", answer(true))
+	r.Register("payout_not_received", answer(false))
+}
+
+// payoutDecision — ответ второй стороны на выплату p.
+func payoutDecision(l i18n.Lang, p domain.Payout, received bool) string {
+	if received {
+		return l.T("payout.confirmed", p.ID, l.Date(p.Date), l.Money(p.Currency, p.Amount))
+	}
+	return l.T("payout.disputed", p.ID, l.Date(p.Date), l.Money(p.Currency, p.Amount))
+}
+
+// notifyPayoutRecorder сообщает записавшему выплату ответ второй стороны.
+func (h *Handler) notifyPayoutRecorder(c telebot.Context, p domain.Payout, received bool) {
+	recorder, err := h.Employees.GetEmployeeByID(int(p.RecordedBy))
+	if err != nil || recorder.ChatID == 0 {
+		return
+	}
+	who := h.actorName(c.Sender().ID)
+	if !p.RecordedByEmployee() {
+		who = h.employeeName(p.EmployeeID)
+	}
+	text := who + ": " + payoutDecision(h.langOf(recorder), p, received)
+	if _, err := h.Bot.Send(telebot.ChatID(recorder.ChatID), text); err != nil {
+		log.Printf("[payout] notify recorder=%d: %v", recorder.ID, err)
+	}
+}
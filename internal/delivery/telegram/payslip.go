@@ -0,0 +1,58 @@
+package telegram
+
+import (
+	"bytes"
+	"log"
+	"strconv"
+	"time"
+
+	"salary-bot/internal/delivery/telegram/keyboards"
+	"salary-bot/internal/delivery/telegram/middleware"
+	"salary-bot/internal/delivery/telegram/router"
+
+	"gopkg.in/telebot.v3"
+)
+
+// Расчётный листок: выбор месяца и отправка HTML-документа.
+func (h *Handler) registerPayslip(r *router.CallbackRouter) {
+	showMonths := func(c telebot.Context, year int) error {
+		l := h.lang(c)
+		title, markup := keyboards.BuildMonthKeyboardFor(l, year, "payslip_month", "payslip_year")
+		return middleware.EditOrSend(c, l.T("payslip.title", title), markup)
+	}
+	r.Register("payslip", func(c telebot.Context, payload string) error {
+		return showMonths(c, h.now(c).Year())
+	})
+	r.Register("payslip_year", func(c telebot.Context, payload string) error {
+		y, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil
+		}
+		return showMonths(c, y)
+	})
+	r.Register("payslip_month", func(c telebot.Context, payload string) error {
+		y, m, ok := keyboards.ParseMonth(payload)
+		if !ok {
+			return nil
+		}
+		return h.sendPayslip(c, time.Date(y, time.Month(m), 1, 0, 0, 0, 0, time.UTC))
+	})
+}
+
+func (h *Handler) sendPayslip(c telebot.Context, month time.Time) error {
+	l := h.lang(c)
+	empID := h.targetID(c)
+	res, err := h.Async.SubmitAsync(func() (any, error) {
+		return h.Payslips.Render(empID, month, l)
+	})
+	if err != nil {
+		log.Printf("[payslip] chat=%d month=%s: %v", c.Chat().ID, month.Format("2006-01"), err)
+		return c.Send(l.T("payslip.failed", errText(l, err)))
+	}
+	_ = middleware.EditOrSend(c, l.T("payslip.for", l.Month(month.Month())+" "+strconv.Itoa(month.Year())), nil)
+	return c.Send(&telebot.Document{
+		File:     telebot.FromReader(bytes.NewReader(res.([]byte))),
+		FileName: "payslip_" + month.Format("2006-01") + ".html",
+		MIME:     "text/html",
+	})
+}
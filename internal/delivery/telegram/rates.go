@@ -0,0 +1,131 @@
+package telegram
+
+import (
+	"strings"
+	"time"
+
+	"salary-bot/internal/delivery/telegram/middleware"
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/i18n"
+	"salary-bot/pkg/money"
+
+	"gopkg.in/telebot.v3"
+)
+
+// /rate — показать ставку, /rate 2500 [ДД.ММ.ГГГГ] — задать свою ставку
+// с даты (по умолчанию с сегодня). Менеджер может задать ставку для роли:
+// /rate role employee 2500 [ДД.ММ.ГГГГ].
+func (h *Handler) handleRate(c telebot.Context) error {
+	me, err := h.registerSender(c)
+	l := h.lang(c)
+	if err != nil {
+		return c.Send(l.T("err.data", errText(l, err)))
+	}
+	args := c.Args()
+	if len(args) == 0 {
+		rate, ok, err := h.Rates.RateFor(me, me.Today())
+		if err != nil {
+			return c.Send(l.T("err.rate", errText(l, err)))
+		}
+		if !ok {
+			return c.Send(l.T("rate.none"))
+		}
+		if me.IsHourly() {
+			return c.Send(l.T("rate.current_hour", l.Money(me.Currency, rate)))
+		}
+		return c.Send(l.T("rate.current_shift", l.Money(me.Currency, rate)))
+	}
+
+	if strings.EqualFold(args[0], "role") {
+		if !me.IsManager() {
+			return c.Send(l.T("rate.role_manager_only"))
+		}
+		if len(args) < 3 {
+			return c.Send(l.T("rate.role_usage"))
+		}
+		role := strings.ToLower(args[1])
+		if role != domain.RoleEmployee && role != domain.RoleManager {
+			return c.Send(l.T("rate.unknown_role", args[1]))
+		}
+		amount, from, err := parseRateArgs(args[2:], me.Today())
+		if err != nil {
+			return c.Send(l.T("rate.role_invalid"))
+		}
+		if err := h.Rates.As(c.Sender().ID).SetRoleRate(role, amount, from); err != nil {
+			return c.Send(l.T("err.rate_save", errText(l, err)))
+		}
+		return c.Send(l.T("rate.role_set", role, l.Number(amount), l.Date(from)))
+	}
+
+	amount, from, err := parseRateArgs(args, me.Today())
+	if err != nil {
+		return c.Send(l.T("rate.invalid"))
+	}
+	if err := h.Rates.As(c.Sender().ID).SetEmployeeRate(me.ID, amount, from); err != nil {
+		return c.Send(l.T("err.rate_save", errText(l, err)))
+	}
+	return c.Send(l.T("rate.set", l.Money(me.Currency, amount), l.Date(from)))
+}
+
+// parseRateArgs разбирает "<ставка> [ДД.ММ.ГГГГ]"; без даты ставка
+// действует с today.
+func parseRateArgs(args []string, today time.Time) (money.Amount, time.Time, error) {
+	amount, err := money.Parse(args[0])
+	if err != nil || amount <= 0 {
+		return 0, time.Time{}, money.ErrInvalidAmount
+	}
+	from := today
+	if len(args) > 1 {
+		from, err = time.Parse("02.01.2006", args[1])
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+	}
+	return amount, from, nil
+}
+
+// shiftRate возвращает ставку сотрудника (см. target) на дату смены, если она задана.
+func (h *Handler) shiftRate(c telebot.Context, date time.Time) (money.Amount, bool) {
+	if h.Rates == nil {
+		return 0, false
+	}
+	e, _ := h.target(c)
+	rate, ok, err := h.Rates.RateFor(e, date)
+	if err != nil {
+		return 0, false
+	}
+	return rate, ok
+}
+
+// handleShiftAtRate — кнопка «по ставке» на шаге ввода суммы смены.
+func (h *Handler) handleShiftAtRate(c telebot.Context) error {
+	l := h.lang(c)
+	conv, err := h.Conversations.Get(c.Chat().ID)
+	if err != nil {
+		return c.Send(l.T("err.generic", errText(l, err)))
+	}
+	if conv.State != domain.StateAwaitShiftAmount {
+		return c.Send(l.T("step.done", l.T("menu.add_shift")))
+	}
+	date, err := time.Parse("2006-01-02", conv.Data["date"])
+	if err != nil {
+		h.cancelFlow(conv.ChatID)
+		return c.Send(l.T("err.date"))
+	}
+	rate, ok := h.shiftRate(c, date)
+	if !ok {
+		return c.Send(l.T("rate.not_set_enter"), cancelMarkupIn(l))
+	}
+	if ok, err := h.Conversations.Complete(conv.ChatID, conv.State); err != nil || !ok {
+		return err
+	}
+	empID := h.targetID(c)
+	shiftID, err := h.Shifts.As(c.Sender().ID).AddShift(empID, date, rate)
+	if err != nil {
+		return c.Send(l.T("shift.add_failed", errText(l, err)))
+	}
+	h.notifyTarget(c, empID, func(l i18n.Lang) string {
+		return l.T("notify.shift_added", l.Date(date), h.formatFor(l, empID, rate))
+	})
+	return middleware.EditOrSend(c, l.T("shift.added_at_rate", h.formatFor(l, empID, rate))+h.requestApproval(c, empID, shiftID), h.recordUndo(c, empID, domain.UndoShiftAdded, shiftID))
+}
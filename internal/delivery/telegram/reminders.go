@@ -0,0 +1,108 @@
+package telegram
+
+import (
+	"log"
+	"time"
+
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/notification"
+
+	"gopkg.in/telebot.v3"
+)
+
+// RunPaydayReminders раз в interval проверяет, у кого из сотрудников по
+// его часовому поясу наступило время напоминания schedule, и рассылает
+// напоминания. Блокирует вызывающего.
+func (h *Handler) RunPaydayReminders(interval time.Duration, schedule notification.Schedule) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	prev := time.Now()
+	for now := range ticker.C {
+		h.sendDueReminders(schedule, prev, now)
+		prev = now
+	}
+}
+
+// sendDueReminders отправляет напоминания тем, у кого момент срабатывания
+// попал в промежуток от последнего напоминания до now. Так напоминание,
+// пропущенное, пока бот был выключен, приходит после запуска. Тем, кому
+// ещё не напоминали, промежуток отсчитывается от prev.
+func (h *Handler) sendDueReminders(schedule notification.Schedule, prev, now time.Time) {
+	employees, err := h.Employees.GetAllEmployees()
+	if err != nil {
+		log.Printf("[reminder] employees: %v", err)
+		return
+	}
+	sent := 0
+	for _, e := range employees {
+		if e.ChatID == 0 {
+			continue
+		}
+		s := schedule
+		s.Location = e.Location()
+		since := e.RemindedAt
+		if since.IsZero() {
+			since = prev
+		}
+		at := lastDue(s, since, now)
+		if at.IsZero() {
+			continue
+		}
+		// отмечаем до отправки, чтобы второй экземпляр бота не напомнил
+		// ещё раз, и снимаем отметку, если отправить не удалось. Отмечаем
+		// и тогда, когда напоминать не о чем: иначе промежуток так и
+		// начинался бы с давно прошедшего дня выплаты
+		if ok, err := h.Employees.ClaimReminder(e.ID, e.RemindedAt, now); err != nil || !ok {
+			if err != nil {
+				log.Printf("[reminder] claim employee=%d: %v", e.ID, err)
+			}
+			continue
+		}
+		unpaid, err := h.Shifts.CalculateUnpaidSalary(e.ID)
+		if err != nil {
+			log.Printf("[reminder] unpaid employee=%d: %v", e.ID, err)
+			h.releaseReminder(e, now)
+			continue
+		}
+		if unpaid.IsZero() {
+			continue
+		}
+		l := h.langOf(e)
+		markup := &telebot.ReplyMarkup{}
+		btnPayout := markup.Data(l.T("reminder.btn_payout"), "payout_start")
+		markup.Inline(markup.Row(btnPayout))
+		msg := l.T("reminder.today", l.DayMonth(at))
+		if domain.DateOf(at) != domain.DateOf(now.In(s.Location)) {
+			msg = l.T("reminder.missed", l.DayMonth(at))
+		}
+		msg += "\n" + l.T("reminder.unpaid", l.Totals(unpaid))
+		if _, err := h.Bot.Send(telebot.ChatID(e.ChatID), msg, markup); err != nil {
+			log.Printf("[reminder] send employee=%d: %v", e.ID, err)
+			h.releaseReminder(e, now)
+			continue
+		}
+		sent++
+	}
+	if sent > 0 {
+		log.Printf("[reminder] payday: sent %d", sent)
+	}
+}
+
+// releaseReminder снимает отметку, поставленную перед отправкой: следующий
+// тик досылает напоминание как пропущенное.
+func (h *Handler) releaseReminder(e domain.Employee, now time.Time) {
+	if err := h.Employees.ReleaseReminder(e.ID, now, e.RemindedAt); err != nil {
+		log.Printf("[reminder] release employee=%d: %v", e.ID, err)
+	}
+}
+
+// lastDue возвращает последний момент срабатывания s в (since, now];
+// нулевое время — если такого нет. После долгого простоя напоминание
+// приходит одно, за последний пропущенный день выплаты.
+func lastDue(s notification.Schedule, since, now time.Time) time.Time {
+	var last time.Time
+	for at := s.Next(since); !at.IsZero() && !at.After(now); at = s.Next(at) {
+		last = at
+	}
+	return last
+}
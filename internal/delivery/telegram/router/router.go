@@ -22,6 +22,15 @@ func (r *CallbackRouter) Register(key string, h HandlerFunc) {
     r.handlers[key] = h
 }
 
+// Keys возвращает зарегистрированные ключи callback.
+func (r *CallbackRouter) Keys() []string {
+    keys := make([]string, 0, len(r.handlers))
+    for key := range r.handlers {
+        keys = append(keys, key)
+    }
+    return keys
+}
+
 
 func (r *CallbackRouter) Attach(bot *telebot.Bot) {
     bot.Handle(telebot.OnCallback, func(c telebot.Context) error {
@@ -0,0 +1,308 @@
+package telegram
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"salary-bot/internal/app/service"
+	"salary-bot/internal/delivery/telegram/keyboards"
+	"salary-bot/internal/delivery/telegram/middleware"
+	"salary-bot/internal/delivery/telegram/router"
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/money"
+
+	"gopkg.in/telebot.v3"
+)
+
+// /shifts — просмотр смен за месяц с правкой суммы, даты и удалением.
+func (h *Handler) handleShifts(c telebot.Context) error {
+	h.cancelFlow(c.Chat().ID)
+	title, markup := keyboards.BuildMonthKeyboardFor(h.lang(c), h.now(c).Year(), "shifts_month", "shifts_year")
+	return c.Send(title, markup)
+}
+
+func (h *Handler) registerShiftBrowser(r *router.CallbackRouter) {
+	r.Register("shifts_year", func(c telebot.Context, payload string) error {
+		y, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil
+		}
+		title, markup := keyboards.BuildMonthKeyboardFor(h.lang(c), y, "shifts_month", "shifts_year")
+		return middleware.EditOrSend(c, title, markup)
+	})
+	r.Register("shifts_month", func(c telebot.Context, payload string) error {
+		y, m, ok := keyboards.ParseMonth(payload)
+		if !ok {
+			return nil
+		}
+		return h.showShiftList(c, y, m)
+	})
+	r.Register("shift_view", func(c telebot.Context, payload string) error {
+		return h.withShift(c, payload, h.showShift)
+	})
+	r.Register("shift_edit_amount", func(c telebot.Context, payload string) error {
+		return h.withShift(c, payload, func(c telebot.Context, sh domain.DomainShift) error {
+			if h.Shifts.As(c.Sender().ID).CanEditShift(sh) != nil {
+				return h.showShift(c, sh)
+			}
+			l := h.lang(c)
+			err := h.Conversations.Set(c.Chat().ID, domain.StateAwaitShiftEditAmount, map[string]string{
+				"shift_id": strconv.Itoa(sh.ID),
+			})
+			if err != nil {
+				return c.Send(l.T("err.generic", errText(l, err)))
+			}
+			return middleware.EditOrSend(c, l.T("shifts.edit_amount", l.Date(sh.Date), l.Money(sh.Currency, sh.Amount)), cancelMarkupIn(l))
+		})
+	})
+	r.Register("shift_edit_date", func(c telebot.Context, payload string) error {
+		return h.withShift(c, payload, func(c telebot.Context, sh domain.DomainShift) error {
+			if h.Shifts.As(c.Sender().ID).CanEditShift(sh) != nil || h.Calendar == nil {
+				return h.showShift(c, sh)
+			}
+			return h.Calendar.ShowCalendar(c, func(date time.Time, c telebot.Context) error {
+				if err := h.Shifts.As(c.Sender().ID).UpdateShiftDate(int(c.Sender().ID), sh.ID, date); err != nil {
+					return h.sendShiftError(c, err)
+				}
+				sh, err := h.Shifts.GetEmployeeShift(int(c.Sender().ID), sh.ID)
+				if err != nil {
+					return h.sendShiftError(c, err)
+				}
+				h.requestApproval(c, sh.EmployeeID, sh.ID)
+				return h.showShift(c, sh)
+			})
+		})
+	})
+	r.Register("shift_delete", func(c telebot.Context, payload string) error {
+		return h.withShift(c, payload, func(c telebot.Context, sh domain.DomainShift) error {
+			if h.Shifts.As(c.Sender().ID).CanEditShift(sh) != nil {
+				return h.showShift(c, sh)
+			}
+			l := h.lang(c)
+			m := &telebot.ReplyMarkup{}
+			yes := m.Data(l.T("shifts.btn_delete_yes"), "shift_delete_confirm", strconv.Itoa(sh.ID))
+			no := m.Data(l.T("btn.back"), "shift_view", strconv.Itoa(sh.ID))
+			m.Inline(m.Row(yes), m.Row(no))
+			return middleware.EditOrSend(c, l.T("shifts.delete_ask", l.Date(sh.Date), l.Money(sh.Currency, sh.Amount)), m)
+		})
+	})
+	r.Register("shift_delete_confirm", func(c telebot.Context, payload string) error {
+		return h.withShift(c, payload, func(c telebot.Context, sh domain.DomainShift) error {
+			if err := h.Shifts.As(c.Sender().ID).DeleteShift(int(c.Sender().ID), sh.ID); err != nil {
+				return h.sendShiftError(c, err)
+			}
+			l := h.lang(c)
+			m := &telebot.ReplyMarkup{}
+			back := m.Data(l.T("shifts.btn_to_list"), "shifts_month", sh.Date.Format("2006-01"))
+			m.Inline(m.Row(back))
+			return middleware.EditOrSend(c, l.T("shifts.deleted", l.Date(sh.Date)), m)
+		})
+	})
+	r.Register("payout_reverse", func(c telebot.Context, payload string) error {
+		p, err := h.employeePayout(c, payload)
+		if err != nil {
+			return h.sendShiftError(c, err)
+		}
+		reversal, err := h.Shifts.As(c.Sender().ID).ReversalOf(p)
+		if err != nil {
+			return h.sendShiftError(c, err)
+		}
+		l := h.lang(c)
+		ask, btn := "payout.reverse_ask", "payout.btn_reverse_yes"
+		if reversal == service.ReversalDispute {
+			ask, btn = "payout.dispute_ask", "payout.btn_dispute_yes"
+		}
+		m := &telebot.ReplyMarkup{}
+		yes := m.Data(l.T(btn), "payout_reverse_confirm", strconv.Itoa(p.ID))
+		no := m.Data(l.T("btn.cancel"), "cancel_flow")
+		m.Inline(m.Row(yes), m.Row(no))
+		return middleware.EditOrSend(c, l.T(ask, p.ID, l.Date(p.Date), l.Money(p.Currency, p.Amount)), m)
+	})
+	r.Register("payout_reverse_confirm", func(c telebot.Context, payload string) error {
+		p, err := h.employeePayout(c, payload)
+		if err != nil {
+			return h.sendShiftError(c, err)
+		}
+		if p, err = h.Shifts.As(c.Sender().ID).ReversePayout(int(c.Sender().ID), p.ID); err != nil {
+			return h.sendShiftError(c, err)
+		}
+		l := h.lang(c)
+		if p.Status == domain.PayoutDisputed {
+			h.notifyPayoutRecorder(c, p, false)
+			return middleware.EditOrSend(c, payoutDecision(l, p, false), nil)
+		}
+		return middleware.EditOrSend(c, l.T("payout.reversed", p.ID, l.Money(p.Currency, p.Amount)), nil)
+	})
+}
+
+func (h *Handler) showShiftList(c telebot.Context, year, month int) error {
+	l := h.lang(c)
+	empID := int(c.Sender().ID)
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC)
+	shifts, err := h.Shifts.GetShifts(empID, from, to)
+	if err != nil {
+		return c.Send(l.T("err.shifts", errText(l, err)))
+	}
+	m := &telebot.ReplyMarkup{}
+	var rows []telebot.Row
+	for _, sh := range shifts {
+		label := l.DayMonth(sh.Date) + " — " + l.Money(sh.Currency, sh.Amount)
+		switch {
+		case sh.Status == domain.ShiftPending:
+			label += " ⏳"
+		case sh.Status == domain.ShiftRejected:
+			label += " ❌"
+		case sh.Paid:
+			label += " ✅"
+		case sh.PaidAmount > 0:
+			label += " ◐"
+		}
+		rows = append(rows, m.Row(m.Data(label, "shift_view", strconv.Itoa(sh.ID))))
+	}
+	rows = append(rows, m.Row(m.Data(l.T("btn.other_month"), "shifts_year", strconv.Itoa(year))))
+	m.Inline(rows...)
+	period := l.Month(time.Month(month)) + " " + strconv.Itoa(year)
+	title := l.T("shifts.title", period)
+	if len(shifts) == 0 {
+		title = l.T("shifts.none", period)
+	}
+	return middleware.EditOrSend(c, title, m)
+}
+
+func (h *Handler) showShift(c telebot.Context, sh domain.DomainShift) error {
+	l := h.lang(c)
+	var b strings.Builder
+	b.WriteString(l.T("shifts.card", l.Date(sh.Date), l.Money(sh.Currency, sh.Amount)) + "\n")
+	if sh.Hourly != nil {
+		b.WriteString(l.T("shifts.card_hourly",
+			domain.FormatClock(sh.Hourly.Start), domain.FormatClock(sh.Hourly.End),
+			sh.Hourly.BreakMinutes, formatWorked(l, sh.Hourly.Worked()), l.Money(sh.Currency, sh.Hourly.Rate)) + "\n")
+	}
+	switch sh.Status {
+	case domain.ShiftPending:
+		b.WriteString(l.T("shifts.card_pending") + "\n")
+	case domain.ShiftRejected:
+		b.WriteString(l.T("shifts.card_rejected") + "\n")
+	}
+	m := &telebot.ReplyMarkup{}
+	id := strconv.Itoa(sh.ID)
+	var rows []telebot.Row
+	if shiftLocked(sh) {
+		payouts, err := h.Shifts.GetShiftPayouts(sh.ID)
+		if err != nil {
+			return c.Send(l.T("err.payouts", errText(l, err)))
+		}
+		b.WriteString(l.T("shifts.card_paid", l.Money(sh.Currency, sh.PaidAmount)))
+		shifts := h.Shifts.As(c.Sender().ID)
+		for _, p := range payouts {
+			reversal, err := shifts.ReversalOf(p)
+			if err != nil {
+				continue
+			}
+			label := l.T("shifts.btn_reverse", p.ID, l.DayMonth(p.Date))
+			if reversal == service.ReversalDispute {
+				label = l.T("shifts.btn_dispute", p.ID, l.DayMonth(p.Date))
+			}
+			rows = append(rows, m.Row(m.Data(label, "payout_reverse", strconv.Itoa(p.ID))))
+		}
+	} else if h.Shifts.As(c.Sender().ID).CanEditShift(sh) != nil {
+		b.WriteString(l.T("shifts.card_approved"))
+	} else {
+		rows = append(rows,
+			m.Row(m.Data(l.T("shifts.btn_amount"), "shift_edit_amount", id), m.Data(l.T("shifts.btn_date"), "shift_edit_date", id)),
+			m.Row(m.Data(l.T("shifts.btn_delete"), "shift_delete", id)),
+		)
+	}
+	rows = append(rows, m.Row(m.Data(l.T("shifts.btn_to_list"), "shifts_month", sh.Date.Format("2006-01"))))
+	m.Inline(rows...)
+	return middleware.EditOrSend(c, b.String(), m)
+}
+
+func (h *Handler) handleShiftEditAmount(c telebot.Context, conv domain.Conversation) error {
+	l := h.lang(c)
+	shiftID, err := strconv.Atoi(conv.Data["shift_id"])
+	if err != nil {
+		h.cancelFlow(conv.ChatID)
+		return c.Send(l.T("shifts.restart"))
+	}
+	amount, err := money.Parse(c.Text())
+	if err != nil {
+		return c.Send(l.T("shift.amount_invalid"), cancelMarkupIn(l))
+	}
+	if amount < money.FromMinor(100) {
+		return c.Send(l.T("shift.amount_min"), cancelMarkupIn(l))
+	}
+	if ok, err := h.Conversations.Complete(conv.ChatID, conv.State); err != nil || !ok {
+		return err
+	}
+	empID := int(c.Sender().ID)
+	if err := h.Shifts.As(c.Sender().ID).UpdateShiftAmount(empID, shiftID, amount); err != nil {
+		return h.sendShiftError(c, err)
+	}
+	sh, err := h.Shifts.GetEmployeeShift(empID, shiftID)
+	if err != nil {
+		return h.sendShiftError(c, err)
+	}
+	h.requestApproval(c, empID, sh.ID)
+	return h.showShift(c, sh)
+}
+
+// withShift разбирает ID смены из payload и проверяет, что она принадлежит отправителю.
+func (h *Handler) withShift(c telebot.Context, payload string, fn func(telebot.Context, domain.DomainShift) error) error {
+	id, err := strconv.Atoi(payload)
+	if err != nil {
+		return nil
+	}
+	sh, err := h.Shifts.GetEmployeeShift(int(c.Sender().ID), id)
+	if err != nil {
+		return h.sendShiftError(c, err)
+	}
+	return fn(c, sh)
+}
+
+func (h *Handler) employeePayout(c telebot.Context, payload string) (domain.Payout, error) {
+	id, err := strconv.Atoi(payload)
+	if err != nil {
+		return domain.Payout{}, domain.ErrPayoutNotFound
+	}
+	payouts, err := h.Shifts.GetPayouts(int(c.Sender().ID), time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Now().AddDate(10, 0, 0))
+	if err != nil {
+		return domain.Payout{}, err
+	}
+	for _, p := range payouts {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return domain.Payout{}, domain.ErrPayoutNotFound
+}
+
+func (h *Handler) sendShiftError(c telebot.Context, err error) error {
+	l := h.lang(c)
+	switch {
+	case errors.Is(err, domain.ErrShiftNotFound):
+		return c.Send(l.T("shifts.not_found"))
+	case errors.Is(err, domain.ErrPayoutNotFound):
+		return c.Send(l.T("payout.not_found"))
+	case errors.Is(err, service.ErrShiftPaid):
+		return c.Send(l.T("shifts.paid"))
+	case errors.Is(err, service.ErrShiftApproved):
+		return c.Send(l.T("shifts.approved_locked"))
+	case errors.Is(err, service.ErrPayoutConfirmed):
+		return c.Send(l.T("payout.confirmed_locked"))
+	case errors.Is(err, service.ErrPayoutReviewed):
+		return c.Send(l.T("payout.already_reviewed"))
+	}
+	return c.Send(l.T("err.generic", errText(l, err)))
+}
+
+// shiftLocked — по смене есть выплаты. Оплаченность держится только на
+// журнале выплат (старые отметки переносит миграция 0015), поэтому у
+// заблокированной смены всегда есть выплата; отменить её можно, пока
+// вторая сторона её не подтвердила.
+func shiftLocked(sh domain.DomainShift) bool {
+	return sh.Paid || sh.PaidAmount > 0
+}
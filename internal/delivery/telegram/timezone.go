@@ -0,0 +1,97 @@
+package telegram
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/i18n"
+
+	"gopkg.in/telebot.v3"
+)
+
+// location — часовой пояс отправителя.
+func (h *Handler) location(c telebot.Context) *time.Location {
+	if c.Sender() == nil {
+		return time.Local
+	}
+	e, err := h.Employees.GetEmployeeByID(int(c.Sender().ID))
+	if err != nil {
+		return time.Local
+	}
+	return e.Location()
+}
+
+// now — текущее время в поясе отправителя: от него считаются «сегодня»
+// и текущий месяц.
+func (h *Handler) now(c telebot.Context) time.Time {
+	return time.Now().In(h.location(c))
+}
+
+// /timezone Europe/Moscow или /timezone +3 — часовой пояс; без аргумента
+// бот показывает текущий и предлагает отправить местоположение.
+func (h *Handler) handleTimeZone(c telebot.Context) error {
+	me, err := h.registerSender(c)
+	l := h.lang(c)
+	if err != nil {
+		return c.Send(l.T("err.data", errText(l, err)))
+	}
+	if len(c.Args()) == 0 {
+		if me.TimeZone != "" {
+			_ = c.Send(l.T("tz.current", me.TimeZone, me.Now().Format("15:04")))
+		}
+		return h.askTimeZone(c)
+	}
+	return h.saveTimeZone(c, strings.Join(c.Args(), " "))
+}
+
+// askTimeZone спрашивает пояс: кнопкой с местоположением или текстом.
+func (h *Handler) askTimeZone(c telebot.Context) error {
+	l := h.lang(c)
+	if err := h.Conversations.Set(c.Chat().ID, domain.StateAwaitTimeZone, nil); err != nil {
+		return c.Send(l.T("err.generic", errText(l, err)))
+	}
+	m := &telebot.ReplyMarkup{ResizeKeyboard: true, OneTimeKeyboard: true}
+	m.Reply(m.Row(m.Location(l.T("tz.btn_location"))), m.Row(m.Text(l.T("tz.btn_skip"))))
+	return c.Send(l.T("tz.ask"), m)
+}
+
+func (h *Handler) handleTimeZoneText(c telebot.Context, conv domain.Conversation) error {
+	if i18n.Is(c.Text(), "tz.btn_skip") {
+		h.cancelFlow(conv.ChatID)
+		l := h.lang(c)
+		return c.Send(l.T("tz.skipped"), mainMenu(l))
+	}
+	return h.saveTimeZone(c, c.Text())
+}
+
+// handleLocation принимает местоположение в ответ на вопрос о поясе.
+func (h *Handler) handleLocation(c telebot.Context) error {
+	conv, err := h.Conversations.Get(c.Chat().ID)
+	if err != nil || conv.State != domain.StateAwaitTimeZone {
+		return err
+	}
+	zone, err := domain.TimeZoneFromLocation(float64(c.Message().Location.Lng))
+	if err != nil {
+		return c.Send(h.lang(c).T("tz.invalid"))
+	}
+	return h.saveTimeZone(c, zone)
+}
+
+func (h *Handler) saveTimeZone(c telebot.Context, text string) error {
+	l := h.lang(c)
+	zone, err := domain.ParseTimeZone(text)
+	if err != nil {
+		return c.Send(l.T("tz.invalid"))
+	}
+	e, err := h.Employees.As(c.Sender().ID).SetTimeZone(int(c.Sender().ID), zone)
+	if errors.Is(err, domain.ErrInvalidTimeZone) {
+		return c.Send(l.T("tz.invalid"))
+	}
+	if err != nil {
+		return c.Send(l.T("err.save", errText(l, err)))
+	}
+	h.cancelFlow(c.Chat().ID)
+	return c.Send(l.T("tz.set", e.TimeZone, e.Now().Format("15:04")), mainMenu(l))
+}
@@ -0,0 +1,77 @@
+package telegram
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"salary-bot/internal/app/service"
+	"salary-bot/internal/delivery/telegram/middleware"
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/i18n"
+
+	"gopkg.in/telebot.v3"
+)
+
+// recordUndo запоминает действие для /undo и возвращает клавиатуру
+// с кнопкой «Отменить». Если запомнить не удалось, кнопки не будет.
+func (h *Handler) recordUndo(c telebot.Context, employeeID int, kind string, refID int) *telebot.ReplyMarkup {
+	if h.Undo == nil || refID == 0 {
+		return nil
+	}
+	actionID, err := h.Undo.Record(c.Sender().ID, employeeID, kind, refID)
+	if err != nil {
+		log.Printf("[undo] record %s ref=%d: %v", kind, refID, err)
+		return nil
+	}
+	m := &telebot.ReplyMarkup{}
+	m.Inline(m.Row(m.Data(h.lang(c).T("undo.btn"), "undo", strconv.Itoa(actionID))))
+	return m
+}
+
+// /undo — отменить последнее добавление смены или выплату.
+func (h *Handler) handleUndo(c telebot.Context) error {
+	h.cancelFlow(c.Chat().ID)
+	l := h.lang(c)
+	a, err := h.Undo.UndoLast(c.Sender().ID)
+	if err != nil {
+		return c.Send(undoErrorText(l, err))
+	}
+	return c.Send(undoDoneText(l, a))
+}
+
+func (h *Handler) handleUndoCallback(c telebot.Context, payload string) error {
+	actionID, err := strconv.Atoi(payload)
+	if err != nil {
+		return nil
+	}
+	l := h.lang(c)
+	a, err := h.Undo.Undo(c.Sender().ID, actionID)
+	if err != nil {
+		return c.Send(undoErrorText(l, err))
+	}
+	return middleware.EditOrSend(c, undoDoneText(l, a), nil)
+}
+
+func undoDoneText(l i18n.Lang, a domain.UndoAction) string {
+	if a.Kind == domain.UndoPayout {
+		return l.T("undo.payout_done")
+	}
+	return l.T("undo.shift_done")
+}
+
+func undoErrorText(l i18n.Lang, err error) string {
+	switch {
+	case errors.Is(err, service.ErrNothingToUndo):
+		return l.T("undo.nothing")
+	case errors.Is(err, service.ErrUndoExpired):
+		return l.T("undo.expired")
+	case errors.Is(err, service.ErrUndoNotLatest):
+		return l.T("undo.not_latest")
+	case errors.Is(err, service.ErrShiftPaid):
+		return l.T("undo.shift_paid")
+	case errors.Is(err, service.ErrPayoutConfirmed):
+		return l.T("payout.confirmed_locked")
+	}
+	return l.T("undo.failed", errText(l, err))
+}
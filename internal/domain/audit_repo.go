@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// Действия в журнале аудита.
+const (
+	AuditCreate    = "create"
+	AuditUpdate    = "update"
+	AuditDelete    = "delete"
+	AuditDeleteAll = "delete_all"
+)
+
+// Сущности в журнале аудита.
+const (
+	EntityShift    = "shift"
+	EntityPayout   = "payout"
+	EntityEmployee = "employee"
+	EntityRateCard = "rate_card"
+)
+
+// AuditEntry — запись журнала изменений. Before и After — JSON-снимки
+// сущности до и после изменения (пустая строка, если снимка нет).
+type AuditEntry struct {
+	ID         int
+	ActorID    int64
+	EmployeeID int
+	Action     string
+	Entity     string
+	EntityID   int
+	Before     string
+	After      string
+	CreatedAt  time.Time
+}
+
+type AuditRepo interface {
+	AppendAudit(e AuditEntry) error
+	// GetAuditEntries возвращает последние записи, сделанные пользователем
+	// или затрагивающие его как сотрудника, от новых к старым.
+	GetAuditEntries(userID int64, limit int) ([]AuditEntry, error)
+	// GetEntityAudit возвращает все записи по сущностям entity сотрудника,
+	// от старых к новым.
+	GetEntityAudit(employeeID int, entity string) ([]AuditEntry, error)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// Состояния диалога с пользователем. Пустое состояние — пользователь
+// ничего не вводит и может выбрать команду из меню.
+const (
+	StateIdle              = ""
+	StateAwaitShiftAmount  = "await_shift_amount"
+	StateAwaitPayoutAmount = "await_payout_amount"
+	StateAwaitShiftTimes   = "await_shift_times"
+	// StateAwaitShiftEditAmount — ввод новой суммы смены, Data["shift_id"].
+	StateAwaitShiftEditAmount = "await_shift_edit_amount"
+	// StateConfirmImport — ждём подтверждения импорта, строки в Data["rows"].
+	StateConfirmImport = "confirm_import"
+	// StateAwaitTimeZone — ждём местоположение или пояс текстом.
+	StateAwaitTimeZone = "await_time_zone"
+)
+
+// Conversation — текущий шаг сценария в чате и собранные на нём данные.
+type Conversation struct {
+	ChatID    int64
+	State     string
+	Data      map[string]string
+	ExpiresAt time.Time
+}
+
+type ConversationRepo interface {
+	// GetConversation возвращает состояние чата; если его нет или оно
+	// истекло, возвращается Conversation с StateIdle.
+	GetConversation(chatID int64, now time.Time) (Conversation, error)
+	SaveConversation(c Conversation) error
+	// DeleteConversation удаляет состояние, только если оно равно state
+	// (пустой state удаляет любое). Возвращает true, если запись была удалена.
+	DeleteConversation(chatID int64, state string) (bool, error)
+	DeleteExpiredConversations(now time.Time) (int64, error)
+}
@@ -1,14 +1,94 @@
 package domain
 
+import (
+	"errors"
+	"time"
+
+	"salary-bot/pkg/money"
+)
+
+var ErrEmployeeNotFound = errors.New("сотрудник не найден")
+
+const (
+	RoleEmployee = "employee"
+	RoleManager  = "manager"
+)
+
+const (
+	PayPerShift = "shift"
+	PayHourly   = "hourly"
+)
+
 type EmployeeRepo interface {
 	GetAllEmployees() ([]Employee, error)
 	GetEmployeeByID(id int) (Employee, error)
 	CreateOrUpdateEmployee(e Employee) error
+	// ClaimPrompt отмечает, что вечерний вопрос за date отправлен. Возвращает
+	// false, если он уже был отправлен в этот день.
+	ClaimPrompt(id int, date time.Time) (bool, error)
+	// ClaimReminder переносит время последнего напоминания о дне выплаты
+	// с prev на now. Возвращает false, если его уже перенесли.
+	ClaimReminder(id int, prev, now time.Time) (bool, error)
+	// ReleasePrompt снимает отметку ClaimPrompt за date, если вопрос не
+	// удалось отправить, — следующий тик попробует снова.
+	ReleasePrompt(id int, date time.Time) error
+	// ReleaseReminder возвращает время последнего напоминания с now на
+	// prev, если напоминание не удалось отправить.
+	ReleaseReminder(id int, now, prev time.Time) error
 }
 
+// Employee.ID совпадает с Telegram ID пользователя.
 type Employee struct {
-	ID     int
-	Name   string
-	ChatID int64
-	Role   string
+	ID       int
+	Name     string
+	Username string
+	ChatID   int64
+	Role     string
+	PayType  string
+	// HourlyRate — ставка за час для PayHourly.
+	HourlyRate money.Amount
+	// PromptTime — время вечернего вопроса «работали сегодня?» (ЧЧ:ММ),
+	// пустая строка — вопрос выключен.
+	PromptTime string
+	// Currency — валюта оплаты; в ней записываются новые смены сотрудника.
+	Currency money.Currency
+	// Language — выбранный через /lang язык (ru, en); пустой — язык
+	// из настроек Telegram.
+	Language string
+	// TimeZone — часовой пояс IANA ("Europe/Moscow", "Etc/GMT-5"); пустой —
+	// пояс сервера. По нему считаются «сегодня», границы месяца и
+	// время напоминаний.
+	TimeZone string
+	// RemindedAt — когда сотруднику последний раз напоминали о дне выплаты;
+	// нулевое — ещё не напоминали. Меняется только через ClaimReminder.
+	RemindedAt time.Time
+}
+
+func (e Employee) IsManager() bool {
+	return e.Role == RoleManager
+}
+
+func (e Employee) IsHourly() bool {
+	return e.PayType == PayHourly
+}
+
+// Location — часовой пояс сотрудника; без TimeZone — пояс сервера.
+func (e Employee) Location() *time.Location {
+	if e.TimeZone != "" {
+		if loc, err := time.LoadLocation(e.TimeZone); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// Now — текущее время в поясе сотрудника.
+func (e Employee) Now() time.Time {
+	return time.Now().In(e.Location())
+}
+
+// Today — сегодняшняя дата сотрудника в том виде, в каком хранятся даты
+// смен: полночь UTC.
+func (e Employee) Today() time.Time {
+	return DateOf(e.Now())
 }
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"salary-bot/pkg/money"
+)
+
+// HourlyShift — детали почасовой смены. Start и End отсчитываются от
+// полуночи дня смены; End <= Start означает, что смена закончилась
+// на следующий день.
+type HourlyShift struct {
+	Start        time.Duration
+	End          time.Duration
+	BreakMinutes int
+	Rate         money.Amount
+}
+
+// Worked — отработанное время за вычетом перерыва.
+func (h HourlyShift) Worked() time.Duration {
+	d := h.End - h.Start
+	if d <= 0 {
+		d += 24 * time.Hour
+	}
+	d -= time.Duration(h.BreakMinutes) * time.Minute
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// Amount — ставка, умноженная на отработанное время, с округлением до копейки.
+func (h HourlyShift) Amount() money.Amount {
+	minutes := int64(h.Worked() / time.Minute)
+	return money.FromMinor((h.Rate.Minor()*minutes + 30) / 60)
+}
+
+// FormatClock форматирует смещение от полуночи как ЧЧ:ММ.
+func FormatClock(d time.Duration) string {
+	d %= 24 * time.Hour
+	return time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC).Add(d).Format("15:04")
+}
+
+// ParseClock разбирает время вида ЧЧ:ММ в смещение от полуночи.
+func ParseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,10 @@
+package domain
+
+// ManagerContextRepo хранит, от имени какого сотрудника сейчас работает
+// менеджер.
+type ManagerContextRepo interface {
+	// GetManagerContext возвращает выбранного сотрудника; ok == false, если не выбран.
+	GetManagerContext(managerID int64) (employeeID int, ok bool, err error)
+	SetManagerContext(managerID int64, employeeID int) error
+	ClearManagerContext(managerID int64) error
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"salary-bot/pkg/money"
+)
+
+var (
+	ErrPayoutNotFound       = errors.New("выплата не найдена")
+	ErrPayoutExceedsBalance = errors.New("сумма выплаты больше невыплаченного остатка")
+)
+
+// Статусы выплаты. Выплату, записанную одной стороной, подтверждает другая:
+// записанную менеджером — сотрудник, записанную сотрудником — менеджер.
+// Оспоренная выплата остаётся в истории, но в расчётах не участвует.
+const (
+	PayoutUnconfirmed = "unconfirmed"
+	PayoutConfirmed   = "confirmed"
+	PayoutDisputed    = "disputed"
+)
+
+// Служебные комментарии выплат. Хранятся кодами, на язык читателя их
+// переводит service.PayoutNote.
+const (
+	// PayoutNoteImport — выплаты, которыми импорт покрывает уже
+	// выплаченные смены.
+	PayoutNoteImport = "import"
+	// PayoutNoteLegacy — выплаты миграции 0015 за смены, отмеченные
+	// выплаченными до журнала выплат.
+	PayoutNoteLegacy = "legacy"
+)
+
+// Payout — факт выплаты сотруднику. Смены, которые она покрывает,
+// связаны с ней через PayoutAllocation, сами суммы смен не меняются.
+type Payout struct {
+	ID         int
+	EmployeeID int
+	Amount     money.Amount
+	Date       time.Time
+	Note       string
+	RecordedBy int64
+	Status     string
+	// RecordedAt — момент записи, от него отсчитывается срок подтверждения.
+	RecordedAt time.Time
+	Currency   money.Currency
+}
+
+// Counted — выплата учитывается в расчётах: оспоренная не учитывается.
+func (p Payout) Counted() bool {
+	return p.Status != PayoutDisputed
+}
+
+// RecordedByEmployee — выплату записал сам сотрудник, подтверждает менеджер.
+func (p Payout) RecordedByEmployee() bool {
+	return p.RecordedBy == int64(p.EmployeeID)
+}
+
+type PayoutAllocation struct {
+	PayoutID int
+	ShiftID  int
+	Amount   money.Amount
+}
+
+// BatchPayout — выплата, которая записывается вместе с новыми сменами
+// (импорт уже выплаченных смен) и целиком покрывает смены с индексами
+// Shifts в добавляемом списке.
+type BatchPayout struct {
+	Payout Payout
+	Shifts []int
+}
+
+// Allocations — распределение выплаты по сменам, получившим ID ids.
+func (b BatchPayout) Allocations(shifts []DomainShift, ids []int) []PayoutAllocation {
+	allocations := make([]PayoutAllocation, 0, len(b.Shifts))
+	for _, i := range b.Shifts {
+		allocations = append(allocations, PayoutAllocation{ShiftID: ids[i], Amount: shifts[i].Amount})
+	}
+	return allocations
+}
+
+type PayoutRepo interface {
+	// CreatePayout записывает выплату; если распределение превышает остаток
+	// смены (её уже оплатили параллельно), возвращает ErrPayoutExceedsBalance
+	// и ничего не сохраняет.
+	CreatePayout(p Payout, allocations []PayoutAllocation) (int, error)
+	GetPayout(id int) (Payout, error)
+	GetPayouts(employeeID int, from, to time.Time) ([]Payout, error)
+	GetAllocations(payoutID int) ([]PayoutAllocation, error)
+	// GetShiftPayouts возвращает выплаты, покрывающие смену.
+	GetShiftPayouts(shiftID int) ([]Payout, error)
+	// UpdatePayoutStatus меняет статус выплаты. Оспоренная выплата теряет
+	// распределение: деньги, которых сотрудник не получил, не закрывают
+	// смены и не уменьшают остаток.
+	UpdatePayoutStatus(id int, status string) error
+	// GetUnconfirmedPayouts возвращает неподтверждённые выплаты, записанные
+	// раньше before, о которых ещё не напоминали.
+	GetUnconfirmedPayouts(before time.Time) ([]Payout, error)
+	// ClaimEscalation отмечает напоминание о выплате. false — уже отмечено.
+	ClaimEscalation(id int, now time.Time) (bool, error)
+	// DeletePayout отменяет выплату: удаляет её распределение и снимает
+	// отметку об оплате с затронутых смен.
+	DeletePayout(id int) error
+	DeleteByEmployee(employeeID int) error
+}
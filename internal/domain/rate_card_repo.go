@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"salary-bot/pkg/money"
+)
+
+// RateCard — ставка, действующая с EffectiveFrom. Карточка либо
+// персональная (EmployeeID != 0), либо общая для роли (Role != "").
+// Для почасовых сотрудников Amount — ставка за час, иначе — за смену.
+type RateCard struct {
+	ID            int
+	EmployeeID    int
+	Role          string
+	Amount        money.Amount
+	EffectiveFrom time.Time
+}
+
+type RateCardRepo interface {
+	AddRateCard(rc RateCard) error
+	// GetEffectiveRate ищет действующую на date ставку: сначала
+	// персональную, затем по роли. ok == false, если ставки нет.
+	GetEffectiveRate(employeeID int, role string, date time.Time) (rc RateCard, ok bool, err error)
+}
@@ -1,20 +1,62 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+
+	"salary-bot/pkg/money"
+)
+
+var ErrShiftNotFound = errors.New("смена не найдена")
+
+// Статусы смены. В зарплату и выплаты идут только подтверждённые смены.
+const (
+	ShiftPending  = "pending"
+	ShiftApproved = "approved"
+	ShiftRejected = "rejected"
+)
 
 type DomainShift struct {
 	ID         int
 	EmployeeID int
 	Date       time.Time
-	Amount     float64
+	Amount     money.Amount
 	Paid       bool
+	// PaidAmount — сколько уже покрыто выплатами (сумма распределений).
+	PaidAmount money.Amount
+	// Hourly заполнено для почасовых смен; Amount в этом случае рассчитан из него.
+	Hourly   *HourlyShift
+	Status   string
+	Currency money.Currency
+}
+
+func (s DomainShift) Approved() bool {
+	return s.Status == ShiftApproved
+}
+
+// Outstanding возвращает невыплаченный остаток по смене. У неподтверждённых
+// смен долга нет.
+func (s DomainShift) Outstanding() money.Amount {
+	if s.Paid || !s.Approved() {
+		return 0
+	}
+	return s.Amount - s.PaidAmount
 }
 
 type ShiftRepo interface {
-	AddShift(shift DomainShift) error
+	AddShift(shift DomainShift) (int, error)
+	// AddShifts добавляет смены и выплаты по ним одной транзакцией: либо
+	// всё, либо ничего. Возвращает ID смен и ID выплат.
+	AddShifts(shifts []DomainShift, payouts []BatchPayout) ([]int, []int, error)
 	GetShifts(employeeID int, from, to time.Time) ([]DomainShift, error)
-	MarkShiftsPaid(employeeID int, from, to time.Time) error
-	MarkShiftPaidByID(id int) error
-	UpdateShiftAmount(id int, amount float64) error
+	GetShiftByID(id int) (DomainShift, error)
+	GetLastShiftDate(employeeID int) (time.Time, error)
+	UpdateShiftAmount(id int, amount money.Amount) error
+	UpdateShiftDate(id int, date time.Time) error
+	UpdateShiftStatus(id int, status string) error
+	// ApproveShifts подтверждает ожидающие смены ids и той же транзакцией
+	// — неподтверждённые выплаты payoutIDs, покрывающие эти смены.
+	ApproveShifts(ids, payoutIDs []int) error
+	DeleteShift(id int) error
 	DeleteByEmployee(employeeID int) error
 }
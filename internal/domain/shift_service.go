@@ -2,12 +2,16 @@ package domain
 
 import (
 	"time"
+
+	"salary-bot/pkg/money"
 )
 
 type ShiftService interface {
-	CalculateSalary(employeeID int, from, to time.Time) (float64, error)
-	MarkShiftsPaid(employeeID int, from, to time.Time) error
-	AddShift(employeeID int, date time.Time, amount float64) error
+	CalculateSalary(employeeID int, from, to time.Time) (money.Totals, error)
+	MarkShiftsPaid(employeeID int, currency money.Currency, from, to time.Time, recordedBy int64) (int, error)
+	MarkShiftsPaidAmount(employeeID int, amount money.Amount, currency money.Currency, recordedBy int64, note string) (int, error)
+	GetPayouts(employeeID int, from, to time.Time) ([]Payout, error)
+	AddShift(employeeID int, date time.Time, amount money.Amount) (int, error)
 	GetShifts(employeeID int, from, to time.Time) ([]Shift, error)
 }
 
@@ -15,6 +19,6 @@ type Shift struct {
 	ID         int
 	EmployeeID int
 	Date       time.Time
-	Amount     float64
+	Amount     money.Amount
 	Paid       bool
 }
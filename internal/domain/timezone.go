@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidTimeZone = errors.New("неизвестный часовой пояс")
+
+// DateOf отбрасывает время и пояс, оставляя календарную дату t в полночь
+// UTC, как даты смен и кнопки календаря.
+func DateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ParseTimeZone разбирает имя пояса IANA ("Europe/Moscow") или смещение
+// в целых часах ("+3", "UTC-5", "GMT+03:00") и возвращает имя IANA.
+func ParseTimeZone(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", ErrInvalidTimeZone
+	}
+	if strings.EqualFold(s, "UTC") || strings.EqualFold(s, "GMT") {
+		return "UTC", nil
+	}
+	if strings.Contains(s, "/") {
+		loc, err := time.LoadLocation(s)
+		if err != nil {
+			return "", ErrInvalidTimeZone
+		}
+		return loc.String(), nil
+	}
+	offset := strings.TrimPrefix(strings.TrimPrefix(strings.ToUpper(s), "UTC"), "GMT")
+	offset = strings.TrimSuffix(offset, ":00")
+	hours, err := strconv.Atoi(offset)
+	if err != nil {
+		return "", ErrInvalidTimeZone
+	}
+	return offsetZone(hours)
+}
+
+// TimeZoneFromLocation подбирает пояс по долготе: 15° на час. Границы
+// реальных поясов так не учесть, но для «сегодня» и времени напоминаний
+// точности до часа хватает.
+func TimeZoneFromLocation(longitude float64) (string, error) {
+	return offsetZone(int(math.Round(longitude / 15)))
+}
+
+// offsetZone возвращает пояс Etc/GMT для смещения от UTC в часах. Знак
+// в именах Etc/GMT обратный: UTC+3 — это Etc/GMT-3.
+func offsetZone(hours int) (string, error) {
+	if hours < -12 || hours > 14 {
+		return "", ErrInvalidTimeZone
+	}
+	if hours == 0 {
+		return "UTC", nil
+	}
+	name := "Etc/GMT" + strconv.Itoa(-hours)
+	if hours < 0 {
+		name = "Etc/GMT+" + strconv.Itoa(-hours)
+	}
+	return name, nil
+}
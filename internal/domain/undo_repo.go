@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// Виды действий, которые можно отменить через /undo.
+const (
+	UndoShiftAdded = "shift_added"
+	UndoPayout     = "payout"
+)
+
+// UndoAction — последнее действие пользователя: RefID указывает на
+// добавленную смену или проведённую выплату.
+type UndoAction struct {
+	ID         int
+	ActorID    int64
+	EmployeeID int
+	Kind       string
+	RefID      int
+	CreatedAt  time.Time
+}
+
+type UndoRepo interface {
+	AddUndoAction(a UndoAction) (int, error)
+	// GetLastUndoAction возвращает последнее действие пользователя; ok == false, если их нет.
+	GetLastUndoAction(actorID int64) (a UndoAction, ok bool, err error)
+	DeleteUndoAction(id int) error
+	// DeleteUndoActionsBefore удаляет действия, записанные раньше before.
+	DeleteUndoActionsBefore(before time.Time) (int64, error)
+}
@@ -1,8 +1,9 @@
 package model
 
 type Employee struct {
-	ID     int
-	Name   string
-	ChatID int64
-	Role   string 
+	ID       int
+	Name     string
+	Username string
+	ChatID   int64
+	Role     string
 }
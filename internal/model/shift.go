@@ -1,11 +1,15 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"salary-bot/pkg/money"
+)
 
 type Shift struct {
 	ID         int
 	EmployeeID int
 	Date       time.Time
-	Amount     float64
+	Amount     money.Amount
 	Paid       bool
 }
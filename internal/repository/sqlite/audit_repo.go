@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"salary-bot/internal/domain"
+)
+
+type SqliteAuditRepo struct {
+	db *sql.DB
+}
+
+func NewSqliteAuditRepo(db *sql.DB) *SqliteAuditRepo {
+	return &SqliteAuditRepo{db: db}
+}
+
+func (r *SqliteAuditRepo) AppendAudit(e domain.AuditEntry) error {
+	_, err := r.db.Exec(
+		`INSERT INTO audit_log (actor_id, employee_id, action, entity, entity_id, before, after, created_at)
+         VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.ActorID, e.EmployeeID, e.Action, e.Entity, e.EntityID,
+		nullString(e.Before), nullString(e.After), e.CreatedAt.Unix(),
+	)
+	return err
+}
+
+func (r *SqliteAuditRepo) GetAuditEntries(userID int64, limit int) ([]domain.AuditEntry, error) {
+	rows, err := r.db.Query(
+		`SELECT id, actor_id, employee_id, action, entity, entity_id, before, after, created_at FROM audit_log
+         WHERE actor_id = ? OR employee_id = ? ORDER BY id DESC LIMIT ?`,
+		userID, userID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanAuditEntries(rows)
+}
+
+func (r *SqliteAuditRepo) GetEntityAudit(employeeID int, entity string) ([]domain.AuditEntry, error) {
+	rows, err := r.db.Query(
+		`SELECT id, actor_id, employee_id, action, entity, entity_id, before, after, created_at FROM audit_log
+         WHERE employee_id = ? AND entity = ? ORDER BY id`,
+		employeeID, entity,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanAuditEntries(rows)
+}
+
+func scanAuditEntries(rows *sql.Rows) ([]domain.AuditEntry, error) {
+	defer rows.Close()
+
+	var entries []domain.AuditEntry
+	for rows.Next() {
+		var (
+			e             domain.AuditEntry
+			before, after sql.NullString
+			createdAt     int64
+		)
+		if err := rows.Scan(&e.ID, &e.ActorID, &e.EmployeeID, &e.Action, &e.Entity, &e.EntityID,
+			&before, &after, &createdAt); err != nil {
+			return nil, err
+		}
+		e.Before = before.String
+		e.After = after.String
+		e.CreatedAt = time.Unix(createdAt, 0)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"salary-bot/internal/domain"
+)
+
+type SqliteConversationRepo struct {
+	db *sql.DB
+}
+
+func NewSqliteConversationRepo(db *sql.DB) *SqliteConversationRepo {
+	return &SqliteConversationRepo{db: db}
+}
+
+func (r *SqliteConversationRepo) GetConversation(chatID int64, now time.Time) (domain.Conversation, error) {
+	conv := domain.Conversation{ChatID: chatID, State: domain.StateIdle}
+	var (
+		data      string
+		expiresAt int64
+	)
+	err := r.db.QueryRow(
+		`SELECT state, data, expires_at FROM conversations WHERE chat_id = ? AND expires_at > ?`,
+		chatID, now.Unix(),
+	).Scan(&conv.State, &data, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return conv, nil
+	}
+	if err != nil {
+		return conv, err
+	}
+	if err := json.Unmarshal([]byte(data), &conv.Data); err != nil {
+		return conv, err
+	}
+	conv.ExpiresAt = time.Unix(expiresAt, 0)
+	return conv, nil
+}
+
+func (r *SqliteConversationRepo) SaveConversation(c domain.Conversation) error {
+	data, err := json.Marshal(c.Data)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(
+		`INSERT INTO conversations (chat_id, state, data, expires_at) VALUES (?, ?, ?, ?)
+         ON CONFLICT(chat_id) DO UPDATE SET state = excluded.state, data = excluded.data, expires_at = excluded.expires_at`,
+		c.ChatID, c.State, string(data), c.ExpiresAt.Unix(),
+	)
+	return err
+}
+
+func (r *SqliteConversationRepo) DeleteConversation(chatID int64, state string) (bool, error) {
+	var (
+		res sql.Result
+		err error
+	)
+	if state == domain.StateIdle {
+		res, err = r.db.Exec(`DELETE FROM conversations WHERE chat_id = ?`, chatID)
+	} else {
+		res, err = r.db.Exec(`DELETE FROM conversations WHERE chat_id = ? AND state = ?`, chatID, state)
+	}
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *SqliteConversationRepo) DeleteExpiredConversations(now time.Time) (int64, error) {
+	res, err := r.db.Exec(`DELETE FROM conversations WHERE expires_at <= ?`, now.Unix())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
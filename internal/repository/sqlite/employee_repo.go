@@ -2,6 +2,9 @@ package sqlite
 
 import (
 	"database/sql"
+	"errors"
+	"time"
+
 	"salary-bot/internal/domain"
 )
 
@@ -9,17 +12,20 @@ type SqliteEmployeeRepo struct {
 	db *sql.DB
 }
 
-
 func (r *SqliteEmployeeRepo) CreateOrUpdateEmployee(e domain.Employee) error {
-	
-	res, err := r.db.Exec(`UPDATE employees SET name = ?, chat_id = ?, role = ? WHERE id = ?`, e.Name, e.ChatID, e.Role, e.ID)
+	res, err := r.db.Exec(
+		`UPDATE employees SET name = ?, username = ?, chat_id = ?, role = ?, pay_type = ?, hourly_rate = ?, prompt_time = ?, currency = ?, language = ?, time_zone = ? WHERE id = ?`,
+		e.Name, e.Username, e.ChatID, e.Role, payType(e), e.HourlyRate, e.PromptTime, e.Currency.OrDefault(), e.Language, e.TimeZone, e.ID,
+	)
 	if err != nil {
 		return err
 	}
 	rows, _ := res.RowsAffected()
 	if rows == 0 {
-		
-		_, err = r.db.Exec(`INSERT INTO employees (id, name, chat_id, role) VALUES (?, ?, ?, ?)`, e.ID, e.Name, e.ChatID, e.Role)
+		_, err = r.db.Exec(
+			`INSERT INTO employees (id, name, username, chat_id, role, pay_type, hourly_rate, prompt_time, currency, language, time_zone) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			e.ID, e.Name, e.Username, e.ChatID, e.Role, payType(e), e.HourlyRate, e.PromptTime, e.Currency.OrDefault(), e.Language, e.TimeZone,
+		)
 		return err
 	}
 	return nil
@@ -30,24 +36,82 @@ func NewSqliteEmployeeRepo(db *sql.DB) *SqliteEmployeeRepo {
 }
 
 func (r *SqliteEmployeeRepo) GetAllEmployees() ([]domain.Employee, error) {
-	rows, err := r.db.Query(`SELECT id, name, chat_id, role FROM employees`)
+	rows, err := r.db.Query(`SELECT ` + employeeColumns + ` FROM employees ORDER BY name`)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var employees []domain.Employee
 	for rows.Next() {
-		var e domain.Employee
-		if err := rows.Scan(&e.ID, &e.Name, &e.ChatID, &e.Role); err != nil {
+		e, err := scanEmployee(rows)
+		if err != nil {
 			return nil, err
 		}
 		employees = append(employees, e)
 	}
-	return employees, nil
+	return employees, rows.Err()
 }
 
 func (r *SqliteEmployeeRepo) GetEmployeeByID(id int) (domain.Employee, error) {
+	e, err := scanEmployee(r.db.QueryRow(`SELECT `+employeeColumns+` FROM employees WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return e, domain.ErrEmployeeNotFound
+	}
+	return e, err
+}
+
+func (r *SqliteEmployeeRepo) ClaimPrompt(id int, date time.Time) (bool, error) {
+	day := date.Format("2006-01-02")
+	res, err := r.db.Exec(`UPDATE employees SET prompt_sent_on = ? WHERE id = ? AND prompt_sent_on != ?`, day, id, day)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *SqliteEmployeeRepo) ClaimReminder(id int, prev, now time.Time) (bool, error) {
+	res, err := r.db.Exec(`UPDATE employees SET reminded_at = ? WHERE id = ? AND reminded_at = ?`, now.Unix(), id, unixOrZero(prev))
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *SqliteEmployeeRepo) ReleasePrompt(id int, date time.Time) error {
+	_, err := r.db.Exec(`UPDATE employees SET prompt_sent_on = '' WHERE id = ? AND prompt_sent_on = ?`, id, date.Format("2006-01-02"))
+	return err
+}
+
+func (r *SqliteEmployeeRepo) ReleaseReminder(id int, now, prev time.Time) error {
+	_, err := r.db.Exec(`UPDATE employees SET reminded_at = ? WHERE id = ? AND reminded_at = ?`, unixOrZero(prev), id, now.Unix())
+	return err
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// employeeColumns — колонки, которые читает scanEmployee, в том же порядке.
+const employeeColumns = `id, name, username, chat_id, role, pay_type, hourly_rate, prompt_time, currency, language, time_zone, reminded_at`
+
+func scanEmployee(row rowScanner) (domain.Employee, error) {
 	var e domain.Employee
-	err := r.db.QueryRow(`SELECT id, name, chat_id, role FROM employees WHERE id = ?`, id).Scan(&e.ID, &e.Name, &e.ChatID, &e.Role)
+	var remindedAt int64
+	err := row.Scan(&e.ID, &e.Name, &e.Username, &e.ChatID, &e.Role, &e.PayType, &e.HourlyRate, &e.PromptTime, &e.Currency, &e.Language, &e.TimeZone, &remindedAt)
+	if remindedAt > 0 {
+		e.RemindedAt = time.Unix(remindedAt, 0)
+	}
 	return e, err
 }
+
+func payType(e domain.Employee) string {
+	if e.PayType == "" {
+		return domain.PayPerShift
+	}
+	return e.PayType
+}
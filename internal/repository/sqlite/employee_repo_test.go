@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"salary-bot/internal/domain"
+)
+
+func TestClaimReminder(t *testing.T) {
+	db := openTestDB(t)
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewSqliteEmployeeRepo(db)
+	if err := repo.CreateOrUpdateEmployee(domain.Employee{ID: 7, Name: "Аня"}); err != nil {
+		t.Fatal(err)
+	}
+	e, err := repo.GetEmployeeByID(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.RemindedAt.IsZero() {
+		t.Fatalf("new employee RemindedAt = %v, want zero", e.RemindedAt)
+	}
+
+	first := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	if ok, err := repo.ClaimReminder(7, time.Time{}, first); err != nil || !ok {
+		t.Fatalf("first claim = %v, %v", ok, err)
+	}
+	// второй экземпляр бота прочитал то же старое значение — не проходит
+	if ok, err := repo.ClaimReminder(7, time.Time{}, first.Add(time.Minute)); err != nil || ok {
+		t.Fatalf("stale claim = %v, %v", ok, err)
+	}
+	// время переживает перезапуск и не затирается сохранением сотрудника
+	e, _ = repo.GetEmployeeByID(7)
+	e.Name = "Анна"
+	if err := repo.CreateOrUpdateEmployee(e); err != nil {
+		t.Fatal(err)
+	}
+	if e, _ = repo.GetEmployeeByID(7); !e.RemindedAt.Equal(first) {
+		t.Fatalf("RemindedAt = %v, want %v", e.RemindedAt, first)
+	}
+	if ok, err := repo.ClaimReminder(7, e.RemindedAt, first.AddDate(0, 0, 15)); err != nil || !ok {
+		t.Fatalf("next claim = %v, %v", ok, err)
+	}
+}
+
+func TestReleaseClaims(t *testing.T) {
+	db := openTestDB(t)
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewSqliteEmployeeRepo(db)
+	if err := repo.CreateOrUpdateEmployee(domain.Employee{ID: 7, Name: "Аня"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// неотправленное напоминание возвращается к прежнему времени, и
+	// следующий тик снова его забирает
+	now := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	if ok, err := repo.ClaimReminder(7, time.Time{}, now); err != nil || !ok {
+		t.Fatalf("claim = %v, %v", ok, err)
+	}
+	if err := repo.ReleaseReminder(7, now, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := repo.GetEmployeeByID(7); !e.RemindedAt.IsZero() {
+		t.Fatalf("RemindedAt after release = %v, want zero", e.RemindedAt)
+	}
+	if ok, err := repo.ClaimReminder(7, time.Time{}, now.Add(time.Minute)); err != nil || !ok {
+		t.Fatalf("claim after release = %v, %v", ok, err)
+	}
+
+	day := mustDate(t, "2024-03-05")
+	if ok, err := repo.ClaimPrompt(7, day); err != nil || !ok {
+		t.Fatalf("prompt claim = %v, %v", ok, err)
+	}
+	if err := repo.ReleasePrompt(7, day); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := repo.ClaimPrompt(7, day); err != nil || !ok {
+		t.Fatalf("prompt claim after release = %v, %v", ok, err)
+	}
+}
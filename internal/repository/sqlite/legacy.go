@@ -0,0 +1,131 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+)
+
+const createShiftsTable = `
+CREATE TABLE IF NOT EXISTS shifts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    employee_id INTEGER NOT NULL,
+    date TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    paid BOOLEAN NOT NULL DEFAULT 0
+);
+`
+
+const createEmployeesTable = `
+CREATE TABLE IF NOT EXISTS employees (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    chat_id INTEGER NOT NULL,
+    role TEXT NOT NULL
+);
+`
+
+const createPayoutsTable = `
+CREATE TABLE IF NOT EXISTS payouts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    employee_id INTEGER NOT NULL,
+    amount INTEGER NOT NULL,
+    date TEXT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    recorded_by INTEGER NOT NULL
+);
+`
+
+const createPayoutAllocationsTable = `
+CREATE TABLE IF NOT EXISTS payout_allocations (
+    payout_id INTEGER NOT NULL REFERENCES payouts(id) ON DELETE CASCADE,
+    shift_id INTEGER NOT NULL REFERENCES shifts(id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL,
+    PRIMARY KEY (payout_id, shift_id)
+);
+`
+
+// upgradeLegacySchema приводит базу, созданную до появления schema_migrations,
+// к виду миграции 0001: старые таблицы создавались через CREATE TABLE IF NOT
+// EXISTS, суммы могли храниться как REAL.
+func upgradeLegacySchema(db *sql.DB) error {
+	for _, stmt := range []string{createShiftsTable, createEmployeesTable, createPayoutsTable, createPayoutAllocationsTable} {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return convertAmountsToMinorUnits(db)
+}
+
+// amountTables — таблицы с денежной колонкой amount и их колонки в порядке DDL.
+var amountTables = []struct {
+	name    string
+	ddl     string
+	columns string
+}{
+	{"shifts", createShiftsTable, "id, employee_id, date, amount, paid"},
+	{"payouts", createPayoutsTable, "id, employee_id, amount, date, note, recorded_by"},
+	{"payout_allocations", createPayoutAllocationsTable, "payout_id, shift_id, amount"},
+}
+
+// convertAmountsToMinorUnits переводит суммы, сохранённые как REAL в рублях,
+// в INTEGER копейки. Таблица пересоздаётся, поэтому повторный запуск
+// ничего не делает: у новой таблицы колонка уже INTEGER.
+func convertAmountsToMinorUnits(db *sql.DB) error {
+	for _, t := range amountTables {
+		colType, err := columnType(db, t.name, "amount")
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(colType, "REAL") {
+			continue
+		}
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		newName := t.name + "_new"
+		ddl := strings.Replace(t.ddl, "CREATE TABLE IF NOT EXISTS "+t.name+" (", "CREATE TABLE "+newName+" (", 1)
+		selectCols := strings.Replace(t.columns, "amount", "CAST(ROUND(amount * 100) AS INTEGER)", 1)
+		stmts := []string{
+			ddl,
+			"INSERT INTO " + newName + " (" + t.columns + ") SELECT " + selectCols + " FROM " + t.name,
+			"DROP TABLE " + t.name,
+			"ALTER TABLE " + newName + " RENAME TO " + t.name,
+		}
+		for _, stmt := range stmts {
+			if _, err := tx.Exec(stmt); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func columnType(db *sql.DB, table, column string) (string, error) {
+	rows, err := db.Query("PRAGMA table_info(" + table + ")")
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return "", err
+		}
+		if name == column {
+			return colType, nil
+		}
+	}
+	return "", rows.Err()
+}
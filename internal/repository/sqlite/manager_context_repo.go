@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type SqliteManagerContextRepo struct {
+	db *sql.DB
+}
+
+func NewSqliteManagerContextRepo(db *sql.DB) *SqliteManagerContextRepo {
+	return &SqliteManagerContextRepo{db: db}
+}
+
+func (r *SqliteManagerContextRepo) GetManagerContext(managerID int64) (int, bool, error) {
+	var employeeID int
+	err := r.db.QueryRow(`SELECT employee_id FROM manager_contexts WHERE manager_id = ?`, managerID).Scan(&employeeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return employeeID, true, nil
+}
+
+func (r *SqliteManagerContextRepo) SetManagerContext(managerID int64, employeeID int) error {
+	_, err := r.db.Exec(
+		`INSERT INTO manager_contexts (manager_id, employee_id, updated_at) VALUES (?, ?, ?)
+         ON CONFLICT(manager_id) DO UPDATE SET employee_id = excluded.employee_id, updated_at = excluded.updated_at`,
+		managerID, employeeID, time.Now().Unix(),
+	)
+	return err
+}
+
+func (r *SqliteManagerContextRepo) ClearManagerContext(managerID int64) error {
+	_, err := r.db.Exec(`DELETE FROM manager_contexts WHERE manager_id = ?`, managerID)
+	return err
+}
@@ -2,33 +2,231 @@ package sqlite
 
 import (
 	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
-const createShiftsTable = `
-CREATE TABLE IF NOT EXISTS shifts (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    employee_id INTEGER NOT NULL,
-    date TEXT NOT NULL,
-    amount REAL NOT NULL,
-    paid BOOLEAN NOT NULL DEFAULT 0
-);
-`
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Файлы миграций называются NNNN_name.up.sql / NNNN_name.down.sql,
+// версии применяются по возрастанию, каждая — в своей транзакции.
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
 
-const createEmployeesTable = `
-CREATE TABLE IF NOT EXISTS employees (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
+const createSchemaMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
     name TEXT NOT NULL,
-    chat_id INTEGER NOT NULL,
-    role TEXT NOT NULL
+    applied_at TEXT NOT NULL
 );
 `
 
+type ErrSchemaTooNew struct {
+	Current int
+	Known   int
+}
+
+func (e ErrSchemaTooNew) Error() string {
+	return fmt.Sprintf("версия схемы БД %d новее известной приложению (%d), обновите бота", e.Current, e.Known)
+}
+
+type ErrNoMigrationsToRollback struct{}
+
+func (e ErrNoMigrationsToRollback) Error() string {
+	return "нет применённых миграций для отката"
+}
+
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]*migration)
+	for _, e := range entries {
+		name := e.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		verStr, title, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("некорректное имя миграции: %s", name)
+		}
+		version, err := strconv.Atoi(verStr)
+		if err != nil {
+			return nil, fmt.Errorf("некорректная версия миграции %s: %w", name, err)
+		}
+		body, err := migrationFiles.ReadFile("migrations/" + name)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, name: title}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.up = string(body)
+		} else {
+			m.down = string(body)
+		}
+	}
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" {
+			return nil, fmt.Errorf("миграция %04d_%s: нет up-файла", m.version, m.name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
+
+// LatestVersion — последняя версия схемы, известная этой сборке.
+func LatestVersion() (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].version, nil
+}
+
+// SchemaVersion возвращает текущую версию схемы БД (0 — миграции не применялись).
+func SchemaVersion(db *sql.DB) (int, error) {
+	var version sql.NullInt64
+	if err := db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, err
+	}
+	return int(version.Int64), nil
+}
+
+// Migrate применяет все неприменённые миграции. Если схема БД новее,
+// чем знает бинарник, возвращает ErrSchemaTooNew и ничего не меняет.
 func Migrate(db *sql.DB) error {
-	if _, err := db.Exec(createShiftsTable); err != nil {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	legacy, err := isLegacyDatabase(db)
+	if err != nil {
+		return err
+	}
+	if legacy {
+		if err := upgradeLegacySchema(db); err != nil {
+			return err
+		}
+	}
+	if _, err := db.Exec(createSchemaMigrationsTable); err != nil {
 		return err
 	}
-	if _, err := db.Exec(createEmployeesTable); err != nil {
+	current, err := SchemaVersion(db)
+	if err != nil {
 		return err
 	}
+	latest := 0
+	if len(migrations) > 0 {
+		latest = migrations[len(migrations)-1].version
+	}
+	if current > latest {
+		return ErrSchemaTooNew{Current: current, Known: latest}
+	}
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := applyMigration(db, m); err != nil {
+			return fmt.Errorf("миграция %04d_%s: %w", m.version, m.name, err)
+		}
+	}
 	return nil
 }
+
+// Rollback откатывает последнюю применённую миграцию.
+func Rollback(db *sql.DB) error {
+	if _, err := db.Exec(createSchemaMigrationsTable); err != nil {
+		return err
+	}
+	current, err := SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if current == 0 {
+		return ErrNoMigrationsToRollback{}
+	}
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if m.version != current {
+			continue
+		}
+		if m.down == "" {
+			return fmt.Errorf("миграция %04d_%s не поддерживает откат", m.version, m.name)
+		}
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		if _, err := tx.Exec(m.down); err != nil {
+			return fmt.Errorf("откат миграции %04d_%s: %w", m.version, m.name, err)
+		}
+		if _, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, m.version); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+	return ErrSchemaTooNew{Current: current, Known: migrations[len(migrations)-1].version}
+}
+
+func applyMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(m.up); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+		m.version, m.name, time.Now().UTC().Format(time.RFC3339),
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// isLegacyDatabase сообщает, создана ли база старой версией бота:
+// таблица shifts уже есть, а schema_migrations ещё нет.
+func isLegacyDatabase(db *sql.DB) (bool, error) {
+	var hasShifts, hasVersions int
+	err := db.QueryRow(`SELECT
+        (SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'shifts'),
+        (SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations')`,
+	).Scan(&hasShifts, &hasVersions)
+	if err != nil {
+		return false, err
+	}
+	return hasShifts == 1 && hasVersions == 0, nil
+}
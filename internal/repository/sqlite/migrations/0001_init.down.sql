@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS payout_allocations;
+DROP TABLE IF EXISTS payouts;
+DROP TABLE IF EXISTS employees;
+DROP TABLE IF EXISTS shifts;
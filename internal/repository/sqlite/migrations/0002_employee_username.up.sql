@@ -0,0 +1 @@
+ALTER TABLE employees ADD COLUMN username TEXT NOT NULL DEFAULT '';
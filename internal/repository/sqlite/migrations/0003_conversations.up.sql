@@ -0,0 +1,8 @@
+CREATE TABLE conversations (
+    chat_id INTEGER PRIMARY KEY,
+    state TEXT NOT NULL,
+    data TEXT NOT NULL DEFAULT '{}',
+    expires_at INTEGER NOT NULL
+);
+
+CREATE INDEX idx_conversations_expires_at ON conversations (expires_at);
@@ -0,0 +1,7 @@
+ALTER TABLE employees DROP COLUMN hourly_rate;
+ALTER TABLE employees DROP COLUMN pay_type;
+
+ALTER TABLE shifts DROP COLUMN hourly_rate;
+ALTER TABLE shifts DROP COLUMN break_minutes;
+ALTER TABLE shifts DROP COLUMN end_time;
+ALTER TABLE shifts DROP COLUMN start_time;
@@ -0,0 +1,7 @@
+ALTER TABLE shifts ADD COLUMN start_time TEXT;
+ALTER TABLE shifts ADD COLUMN end_time TEXT;
+ALTER TABLE shifts ADD COLUMN break_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE shifts ADD COLUMN hourly_rate INTEGER;
+
+ALTER TABLE employees ADD COLUMN pay_type TEXT NOT NULL DEFAULT 'shift';
+ALTER TABLE employees ADD COLUMN hourly_rate INTEGER NOT NULL DEFAULT 0;
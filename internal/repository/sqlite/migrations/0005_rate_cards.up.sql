@@ -0,0 +1,11 @@
+CREATE TABLE rate_cards (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    employee_id INTEGER,
+    role TEXT,
+    amount INTEGER NOT NULL,
+    effective_from TEXT NOT NULL,
+    CHECK ((employee_id IS NULL) <> (role IS NULL))
+);
+
+CREATE INDEX idx_rate_cards_employee ON rate_cards (employee_id, effective_from);
+CREATE INDEX idx_rate_cards_role ON rate_cards (role, effective_from);
@@ -0,0 +1,10 @@
+CREATE TABLE undo_actions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor_id INTEGER NOT NULL,
+    employee_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    ref_id INTEGER NOT NULL,
+    created_at INTEGER NOT NULL
+);
+
+CREATE INDEX idx_undo_actions_actor ON undo_actions (actor_id, id);
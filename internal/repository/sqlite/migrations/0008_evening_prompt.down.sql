@@ -0,0 +1,2 @@
+ALTER TABLE employees DROP COLUMN prompt_sent_on;
+ALTER TABLE employees DROP COLUMN prompt_time;
@@ -0,0 +1,2 @@
+ALTER TABLE employees ADD COLUMN prompt_time TEXT NOT NULL DEFAULT '';
+ALTER TABLE employees ADD COLUMN prompt_sent_on TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+CREATE TABLE manager_contexts (
+    manager_id INTEGER PRIMARY KEY,
+    employee_id INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
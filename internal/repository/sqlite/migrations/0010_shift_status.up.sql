@@ -0,0 +1 @@
+ALTER TABLE shifts ADD COLUMN status TEXT NOT NULL DEFAULT 'approved';
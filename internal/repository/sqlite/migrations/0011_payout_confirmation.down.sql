@@ -0,0 +1,3 @@
+ALTER TABLE payouts DROP COLUMN escalated_at;
+ALTER TABLE payouts DROP COLUMN recorded_at;
+ALTER TABLE payouts DROP COLUMN status;
@@ -0,0 +1,3 @@
+ALTER TABLE payouts ADD COLUMN status TEXT NOT NULL DEFAULT 'confirmed';
+ALTER TABLE payouts ADD COLUMN recorded_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE payouts ADD COLUMN escalated_at INTEGER NOT NULL DEFAULT 0;
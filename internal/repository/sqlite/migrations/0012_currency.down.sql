@@ -0,0 +1,3 @@
+ALTER TABLE payouts DROP COLUMN currency;
+ALTER TABLE shifts DROP COLUMN currency;
+ALTER TABLE employees DROP COLUMN currency;
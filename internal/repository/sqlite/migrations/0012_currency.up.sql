@@ -0,0 +1,3 @@
+ALTER TABLE employees ADD COLUMN currency TEXT NOT NULL DEFAULT 'RUB';
+ALTER TABLE shifts ADD COLUMN currency TEXT NOT NULL DEFAULT 'RUB';
+ALTER TABLE payouts ADD COLUMN currency TEXT NOT NULL DEFAULT 'RUB';
@@ -0,0 +1 @@
+ALTER TABLE employees ADD COLUMN language TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE employees ADD COLUMN time_zone TEXT NOT NULL DEFAULT '';
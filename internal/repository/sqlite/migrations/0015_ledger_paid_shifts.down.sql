@@ -0,0 +1,5 @@
+-- Смены остаются с paid = 1, как до миграции.
+DELETE FROM payout_allocations WHERE payout_id IN (
+    SELECT id FROM payouts WHERE note = 'отмечено выплаченным до журнала выплат'
+);
+DELETE FROM payouts WHERE note = 'отмечено выплаченным до журнала выплат';
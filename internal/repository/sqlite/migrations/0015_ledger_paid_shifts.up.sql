@@ -0,0 +1,15 @@
+-- Смены, отмеченные выплаченными до журнала выплат (paid = 1 без
+-- распределений на всю сумму), получают выплату на непокрытый остаток:
+-- так их можно отменить в /shifts, как любую другую выплату.
+INSERT INTO payouts (employee_id, amount, date, note, recorded_by, status, recorded_at, currency)
+SELECT employee_id,
+       amount - (SELECT COALESCE(SUM(a.amount), 0) FROM payout_allocations a WHERE a.shift_id = shifts.id),
+       date, '0015:' || id, employee_id, 'confirmed', 0, currency
+FROM shifts
+WHERE paid = 1
+  AND amount > (SELECT COALESCE(SUM(a.amount), 0) FROM payout_allocations a WHERE a.shift_id = shifts.id);
+
+INSERT INTO payout_allocations (payout_id, shift_id, amount)
+SELECT p.id, s.id, p.amount FROM payouts p JOIN shifts s ON p.note = '0015:' || s.id;
+
+UPDATE payouts SET note = 'отмечено выплаченным до журнала выплат' WHERE note LIKE '0015:%';
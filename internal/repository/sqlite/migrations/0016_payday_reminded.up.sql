@@ -0,0 +1,3 @@
+-- время последнего напоминания о дне выплаты (unix), 0 — ещё не было;
+-- по нему бот после простоя досылает пропущенное напоминание
+ALTER TABLE employees ADD COLUMN reminded_at INTEGER NOT NULL DEFAULT 0;
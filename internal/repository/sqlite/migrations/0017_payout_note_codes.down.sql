@@ -0,0 +1,2 @@
+UPDATE payouts SET note = 'импорт' WHERE note = 'import';
+UPDATE payouts SET note = 'отмечено выплаченным до журнала выплат' WHERE note = 'legacy';
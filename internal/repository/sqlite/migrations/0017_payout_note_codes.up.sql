@@ -0,0 +1,3 @@
+-- Служебные комментарии выплат хранятся кодами и переводятся при выводе.
+UPDATE payouts SET note = 'import' WHERE note = 'импорт';
+UPDATE payouts SET note = 'legacy' WHERE note = 'отмечено выплаченным до журнала выплат';
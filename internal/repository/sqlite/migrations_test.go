@@ -0,0 +1,194 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"salary-bot/internal/domain"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// openTestDB открывает отдельную базу в памяти. Соединение одно: у каждого
+// соединения с :memory: была бы своя база.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func mustDate(t *testing.T, s string) time.Time {
+	t.Helper()
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestMigrateUpDown(t *testing.T) {
+	db := openTestDB(t)
+	latest, err := LatestVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if v, _ := SchemaVersion(db); v != latest {
+		t.Fatalf("version after Migrate = %d, want %d", v, latest)
+	}
+	// повторный запуск ничего не делает
+	if err := Migrate(db); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+
+	for want := latest - 1; want >= 0; want-- {
+		if err := Rollback(db); err != nil {
+			t.Fatalf("Rollback to %d: %v", want, err)
+		}
+		if v, _ := SchemaVersion(db); v != want {
+			t.Fatalf("version after Rollback = %d, want %d", v, want)
+		}
+	}
+	if err := Rollback(db); !errors.As(err, &ErrNoMigrationsToRollback{}) {
+		t.Fatalf("Rollback on empty schema = %v, want ErrNoMigrationsToRollback", err)
+	}
+	var tables int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table'
+        AND name NOT IN ('schema_migrations', 'sqlite_sequence')`).Scan(&tables); err != nil {
+		t.Fatal(err)
+	}
+	if tables != 0 {
+		t.Errorf("%d tables left after rolling everything back", tables)
+	}
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate after full rollback: %v", err)
+	}
+	if v, _ := SchemaVersion(db); v != latest {
+		t.Fatalf("version after re-Migrate = %d, want %d", v, latest)
+	}
+}
+
+func TestMigrateSchemaTooNew(t *testing.T) {
+	db := openTestDB(t)
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	latest, _ := LatestVersion()
+	if _, err := db.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, 'future', '')`, latest+1); err != nil {
+		t.Fatal(err)
+	}
+	var tooNew ErrSchemaTooNew
+	if err := Migrate(db); !errors.As(err, &tooNew) {
+		t.Fatalf("Migrate = %v, want ErrSchemaTooNew", err)
+	}
+	if tooNew.Current != latest+1 || tooNew.Known != latest {
+		t.Errorf("ErrSchemaTooNew = %+v", tooNew)
+	}
+}
+
+func TestMigrateLegacySchema(t *testing.T) {
+	db := openTestDB(t)
+	// так таблицы создавала версия бота до миграций: суммы в рублях как REAL
+	for _, stmt := range []string{
+		`CREATE TABLE shifts (id INTEGER PRIMARY KEY AUTOINCREMENT, employee_id INTEGER NOT NULL,
+            date TEXT NOT NULL, amount REAL NOT NULL, paid BOOLEAN NOT NULL DEFAULT 0)`,
+		`CREATE TABLE employees (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL,
+            chat_id INTEGER NOT NULL, role TEXT NOT NULL)`,
+		`INSERT INTO employees (id, name, chat_id, role) VALUES (7, 'Иван', 7, 'employee')`,
+		`INSERT INTO shifts (employee_id, date, amount, paid) VALUES (7, '2024-03-01', 1500.5, 0)`,
+		`INSERT INTO shifts (employee_id, date, amount, paid) VALUES (7, '2024-03-02', 0.1, 0)`,
+		`INSERT INTO shifts (employee_id, date, amount, paid) VALUES (7, '2024-03-03', 700, 1)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate legacy: %v", err)
+	}
+	latest, _ := LatestVersion()
+	if v, _ := SchemaVersion(db); v != latest {
+		t.Fatalf("version = %d, want %d", v, latest)
+	}
+	if typ, _ := columnType(db, "shifts", "amount"); typ != "INTEGER" {
+		t.Errorf("shifts.amount type = %q, want INTEGER", typ)
+	}
+
+	shifts, err := NewSqliteShiftRepo(db).GetShifts(7, mustDate(t, "2024-01-01"), mustDate(t, "2024-12-31"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shifts) != 3 || shifts[0].Amount.Minor() != 150050 || shifts[1].Amount.Minor() != 10 {
+		t.Fatalf("shifts after upgrade = %+v", shifts)
+	}
+
+	// оплаченная без журнала смена получила выплату, и её можно отменить
+	paid := shifts[2]
+	if !paid.Paid || paid.PaidAmount.Minor() != 70000 {
+		t.Fatalf("paid shift after upgrade = %+v", paid)
+	}
+	payouts := NewSqlitePayoutRepo(db)
+	ledger, err := payouts.GetShiftPayouts(paid.ID)
+	if err != nil || len(ledger) != 1 || ledger[0].Amount.Minor() != 70000 || !ledger[0].Date.Equal(paid.Date) {
+		t.Fatalf("payouts of paid shift = %+v, %v", ledger, err)
+	}
+	if err := payouts.DeletePayout(ledger[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if sh, _ := NewSqliteShiftRepo(db).GetShiftByID(paid.ID); sh.Paid || sh.PaidAmount != 0 {
+		t.Errorf("shift after reversing its payout = %+v", sh)
+	}
+	e, err := NewSqliteEmployeeRepo(db).GetEmployeeByID(7)
+	if err != nil || e.Name != "Иван" {
+		t.Fatalf("employee after upgrade = %+v, %v", e, err)
+	}
+}
+
+func TestMigratePayoutNoteCodes(t *testing.T) {
+	db := openTestDB(t)
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := Rollback(db); err != nil {
+		t.Fatal(err)
+	}
+	// до 0017 служебные комментарии хранились текстом
+	if _, err := db.Exec(`INSERT INTO payouts (employee_id, amount, date, note, recorded_by) VALUES
+        (1, 100, '2024-03-01', 'импорт', 1), (1, 100, '2024-03-01', 'отмечено выплаченным до журнала выплат', 1),
+        (1, 100, '2024-03-01', 'наличными', 1)`); err != nil {
+		t.Fatal(err)
+	}
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := db.Query(`SELECT note FROM payouts ORDER BY id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var notes []string
+	for rows.Next() {
+		var note string
+		if err := rows.Scan(&note); err != nil {
+			t.Fatal(err)
+		}
+		notes = append(notes, note)
+	}
+	want := []string{domain.PayoutNoteImport, domain.PayoutNoteLegacy, "наличными"}
+	if strings.Join(notes, ",") != strings.Join(want, ",") {
+		t.Errorf("notes = %q, want %q", notes, want)
+	}
+}
@@ -0,0 +1,279 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"salary-bot/internal/domain"
+)
+
+type SqlitePayoutRepo struct {
+	db *sql.DB
+}
+
+func NewSqlitePayoutRepo(db *sql.DB) *SqlitePayoutRepo {
+	return &SqlitePayoutRepo{db: db}
+}
+
+// CreatePayout сохраняет выплату вместе с распределением по сменам
+// и отмечает полностью покрытые смены как выплаченные.
+func (r *SqlitePayoutRepo) CreatePayout(p domain.Payout, allocations []domain.PayoutAllocation) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	id, err := insertPayout(tx, p, allocations)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// insertPayout записывает выплату в транзакции tx. Остаток каждой смены
+// проверяется после вставки выплаты: она берёт блокировку на запись, так
+// что одновременная выплата по тем же сменам ждёт коммита и видит её
+// распределение.
+func insertPayout(tx *sql.Tx, p domain.Payout, allocations []domain.PayoutAllocation) (int, error) {
+	res, err := tx.Exec(
+		`INSERT INTO payouts (employee_id, amount, date, note, recorded_by, status, recorded_at, currency) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.EmployeeID,
+		p.Amount,
+		p.Date.Format("2006-01-02"),
+		p.Note,
+		p.RecordedBy,
+		payoutStatus(p),
+		p.RecordedAt.Unix(),
+		p.Currency.OrDefault(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	for _, a := range allocations {
+		var outstanding int64
+		var paid bool
+		err := tx.QueryRow(
+			`SELECT amount - (
+                SELECT COALESCE(SUM(amount), 0) FROM payout_allocations WHERE shift_id = shifts.id
+            ), paid FROM shifts WHERE id = ? AND employee_id = ?`,
+			a.ShiftID, p.EmployeeID,
+		).Scan(&outstanding, &paid)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, domain.ErrShiftNotFound
+		}
+		if err != nil {
+			return 0, err
+		}
+		if paid || a.Amount.Minor() > outstanding {
+			return 0, domain.ErrPayoutExceedsBalance
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO payout_allocations (payout_id, shift_id, amount) VALUES (?, ?, ?)`,
+			id, a.ShiftID, a.Amount,
+		); err != nil {
+			return 0, err
+		}
+		if err := updateShiftPaid(tx, a.ShiftID); err != nil {
+			return 0, err
+		}
+	}
+	return int(id), nil
+}
+
+// updateShiftPaid пересчитывает отметку «выплачено» по распределениям.
+func updateShiftPaid(db execer, shiftID int) error {
+	_, err := db.Exec(
+		`UPDATE shifts SET paid = (
+            SELECT COALESCE(SUM(amount), 0) FROM payout_allocations WHERE shift_id = shifts.id
+        ) >= amount WHERE id = ?`,
+		shiftID,
+	)
+	return err
+}
+
+// payoutStatus — выплаты без статуса считаются подтверждёнными.
+func payoutStatus(p domain.Payout) string {
+	if p.Status == "" {
+		return domain.PayoutConfirmed
+	}
+	return p.Status
+}
+
+const payoutColumns = `id, employee_id, amount, date, note, recorded_by, status, recorded_at, currency`
+
+func scanPayout(row rowScanner) (domain.Payout, error) {
+	var p domain.Payout
+	var dateStr string
+	var recordedAt int64
+	if err := row.Scan(&p.ID, &p.EmployeeID, &p.Amount, &dateStr, &p.Note, &p.RecordedBy, &p.Status, &recordedAt, &p.Currency); err != nil {
+		return p, err
+	}
+	if recordedAt > 0 {
+		p.RecordedAt = time.Unix(recordedAt, 0)
+	}
+	var err error
+	p.Date, err = time.Parse("2006-01-02", dateStr)
+	return p, err
+}
+
+func (r *SqlitePayoutRepo) GetPayout(id int) (domain.Payout, error) {
+	p, err := scanPayout(r.db.QueryRow(`SELECT `+payoutColumns+` FROM payouts WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return p, domain.ErrPayoutNotFound
+	}
+	return p, err
+}
+
+func (r *SqlitePayoutRepo) GetPayouts(employeeID int, from, to time.Time) ([]domain.Payout, error) {
+	return r.queryPayouts(
+		`SELECT `+payoutColumns+` FROM payouts
+         WHERE employee_id = ? AND date BETWEEN ? AND ? ORDER BY date, id`,
+		employeeID,
+		from.Format("2006-01-02"),
+		to.Format("2006-01-02"),
+	)
+}
+
+func (r *SqlitePayoutRepo) GetShiftPayouts(shiftID int) ([]domain.Payout, error) {
+	return r.queryPayouts(
+		`SELECT `+payoutColumns+` FROM payouts
+         WHERE id IN (SELECT payout_id FROM payout_allocations WHERE shift_id = ?) ORDER BY date, id`,
+		shiftID,
+	)
+}
+
+func (r *SqlitePayoutRepo) UpdatePayoutStatus(id int, status string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE payouts SET status = ? WHERE id = ?`, status, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrPayoutNotFound
+	}
+	if status == domain.PayoutDisputed {
+		if err := releaseAllocations(tx, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// releaseAllocations снимает распределение выплаты: покрытые ею смены
+// снова невыплачены.
+func releaseAllocations(tx *sql.Tx, payoutID int) error {
+	if _, err := tx.Exec(
+		`UPDATE shifts SET paid = 0 WHERE id IN (SELECT shift_id FROM payout_allocations WHERE payout_id = ?)`,
+		payoutID,
+	); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`DELETE FROM payout_allocations WHERE payout_id = ?`, payoutID)
+	return err
+}
+
+func (r *SqlitePayoutRepo) GetUnconfirmedPayouts(before time.Time) ([]domain.Payout, error) {
+	return r.queryPayouts(
+		`SELECT `+payoutColumns+` FROM payouts
+         WHERE status = ? AND escalated_at = 0 AND recorded_at <= ? ORDER BY recorded_at, id`,
+		domain.PayoutUnconfirmed,
+		before.Unix(),
+	)
+}
+
+func (r *SqlitePayoutRepo) ClaimEscalation(id int, now time.Time) (bool, error) {
+	res, err := r.db.Exec(`UPDATE payouts SET escalated_at = ? WHERE id = ? AND escalated_at = 0`, now.Unix(), id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (r *SqlitePayoutRepo) queryPayouts(query string, args ...any) ([]domain.Payout, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payouts []domain.Payout
+	for rows.Next() {
+		p, err := scanPayout(rows)
+		if err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, p)
+	}
+	return payouts, rows.Err()
+}
+
+func (r *SqlitePayoutRepo) GetAllocations(payoutID int) ([]domain.PayoutAllocation, error) {
+	rows, err := r.db.Query(
+		`SELECT payout_id, shift_id, amount FROM payout_allocations WHERE payout_id = ? ORDER BY shift_id`,
+		payoutID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var allocations []domain.PayoutAllocation
+	for rows.Next() {
+		var a domain.PayoutAllocation
+		if err := rows.Scan(&a.PayoutID, &a.ShiftID, &a.Amount); err != nil {
+			return nil, err
+		}
+		allocations = append(allocations, a)
+	}
+	return allocations, rows.Err()
+}
+
+func (r *SqlitePayoutRepo) DeletePayout(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := releaseAllocations(tx, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM payouts WHERE id = ?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *SqlitePayoutRepo) DeleteByEmployee(employeeID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		`DELETE FROM payout_allocations WHERE payout_id IN (SELECT id FROM payouts WHERE employee_id = ?)`,
+		employeeID,
+	); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM payouts WHERE employee_id = ?`, employeeID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"salary-bot/internal/domain"
+	"salary-bot/pkg/money"
+)
+
+func TestPayoutAllocations(t *testing.T) {
+	db := openTestDB(t)
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	shifts, payouts := NewSqliteShiftRepo(db), NewSqlitePayoutRepo(db)
+	shiftID, err := shifts.AddShift(domain.DomainShift{EmployeeID: 7, Date: mustDate(t, "2024-03-01"), Amount: 100000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pay := func(amount int64, status string) (int, error) {
+		return payouts.CreatePayout(domain.Payout{
+			EmployeeID: 7, Amount: money.FromMinor(amount), Date: mustDate(t, "2024-03-05"),
+			RecordedBy: 1, Status: status, RecordedAt: time.Now(),
+		}, []domain.PayoutAllocation{{ShiftID: shiftID, Amount: money.FromMinor(amount)}})
+	}
+
+	disputedID, err := pay(60000, domain.PayoutUnconfirmed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// остаток 400: выплата на 500 не проходит и ничего не оставляет
+	if _, err := pay(50000, domain.PayoutConfirmed); !errors.Is(err, domain.ErrPayoutExceedsBalance) {
+		t.Fatalf("overpay = %v, want ErrPayoutExceedsBalance", err)
+	}
+	if list, _ := payouts.GetPayouts(7, mustDate(t, "2024-01-01"), mustDate(t, "2024-12-31")); len(list) != 1 {
+		t.Fatalf("payouts after rejected overpay = %+v", list)
+	}
+	if _, err := pay(40000, domain.PayoutConfirmed); err != nil {
+		t.Fatal(err)
+	}
+	if sh, _ := shifts.GetShiftByID(shiftID); !sh.Paid || sh.PaidAmount != 100000 {
+		t.Fatalf("fully paid shift = %+v", sh)
+	}
+
+	// оспоренная выплата больше не покрывает смену
+	if err := payouts.UpdatePayoutStatus(disputedID, domain.PayoutDisputed); err != nil {
+		t.Fatal(err)
+	}
+	sh, _ := shifts.GetShiftByID(shiftID)
+	if sh.Paid || sh.PaidAmount != 40000 {
+		t.Fatalf("shift after dispute = %+v", sh)
+	}
+	if a, _ := payouts.GetAllocations(disputedID); len(a) != 0 {
+		t.Errorf("disputed payout allocations = %+v", a)
+	}
+	if p, _ := payouts.GetPayout(disputedID); p.Status != domain.PayoutDisputed {
+		t.Errorf("disputed payout = %+v", p)
+	}
+}
@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"salary-bot/internal/domain"
+)
+
+type SqliteRateCardRepo struct {
+	db *sql.DB
+}
+
+func NewSqliteRateCardRepo(db *sql.DB) *SqliteRateCardRepo {
+	return &SqliteRateCardRepo{db: db}
+}
+
+func (r *SqliteRateCardRepo) AddRateCard(rc domain.RateCard) error {
+	var (
+		employeeID sql.NullInt64
+		role       sql.NullString
+	)
+	if rc.EmployeeID != 0 {
+		employeeID = sql.NullInt64{Int64: int64(rc.EmployeeID), Valid: true}
+	} else {
+		role = sql.NullString{String: rc.Role, Valid: true}
+	}
+	_, err := r.db.Exec(
+		`INSERT INTO rate_cards (employee_id, role, amount, effective_from) VALUES (?, ?, ?, ?)`,
+		employeeID, role, rc.Amount, rc.EffectiveFrom.Format("2006-01-02"),
+	)
+	return err
+}
+
+func (r *SqliteRateCardRepo) GetEffectiveRate(employeeID int, role string, date time.Time) (domain.RateCard, bool, error) {
+	day := date.Format("2006-01-02")
+	rc, err := r.scanOne(r.db.QueryRow(
+		`SELECT id, employee_id, role, amount, effective_from FROM rate_cards
+         WHERE employee_id = ? AND effective_from <= ? ORDER BY effective_from DESC, id DESC LIMIT 1`,
+		employeeID, day,
+	))
+	if err == nil {
+		return rc, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return rc, false, err
+	}
+	rc, err = r.scanOne(r.db.QueryRow(
+		`SELECT id, employee_id, role, amount, effective_from FROM rate_cards
+         WHERE role = ? AND effective_from <= ? ORDER BY effective_from DESC, id DESC LIMIT 1`,
+		role, day,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return rc, false, nil
+	}
+	return rc, err == nil, err
+}
+
+func (r *SqliteRateCardRepo) scanOne(row *sql.Row) (domain.RateCard, error) {
+	var (
+		rc         domain.RateCard
+		employeeID sql.NullInt64
+		role       sql.NullString
+		from       string
+	)
+	if err := row.Scan(&rc.ID, &employeeID, &role, &rc.Amount, &from); err != nil {
+		return rc, err
+	}
+	rc.EmployeeID = int(employeeID.Int64)
+	rc.Role = role.String
+	var err error
+	rc.EffectiveFrom, err = time.Parse("2006-01-02", from)
+	return rc, err
+}
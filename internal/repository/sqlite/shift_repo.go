@@ -2,38 +2,129 @@ package sqlite
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 
 	"salary-bot/internal/domain"
+	"salary-bot/pkg/money"
 )
 
 type SqliteShiftRepo struct {
 	db *sql.DB
 }
 
-func (r *SqliteShiftRepo) MarkShiftPaidByID(id int) error {
-	_, err := r.db.Exec(`UPDATE shifts SET paid = 1 WHERE id = ?`, id)
-	return err
-}
-
 func NewSqliteShiftRepo(db *sql.DB) *SqliteShiftRepo {
 	return &SqliteShiftRepo{db: db}
 }
 
-func (r *SqliteShiftRepo) AddShift(shift domain.DomainShift) error {
-	_, err := r.db.Exec(
-		`INSERT INTO shifts (employee_id, date, amount, paid) VALUES (?, ?, ?, ?)`,
+func (r *SqliteShiftRepo) AddShift(shift domain.DomainShift) (int, error) {
+	return insertShift(r.db, shift)
+}
+
+func (r *SqliteShiftRepo) AddShifts(shifts []domain.DomainShift, payouts []domain.BatchPayout) ([]int, []int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+	ids := make([]int, 0, len(shifts))
+	for _, s := range shifts {
+		id, err := insertShift(tx, s)
+		if err != nil {
+			return nil, nil, err
+		}
+		ids = append(ids, id)
+	}
+	payoutIDs := make([]int, 0, len(payouts))
+	for _, p := range payouts {
+		id, err := insertPayout(tx, p.Payout, p.Allocations(shifts, ids))
+		if err != nil {
+			return nil, nil, err
+		}
+		payoutIDs = append(payoutIDs, id)
+	}
+	return ids, payoutIDs, tx.Commit()
+}
+
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func insertShift(db execer, shift domain.DomainShift) (int, error) {
+	start, end, breakMinutes, rate := hourlyColumns(shift.Hourly)
+	status := shift.Status
+	if status == "" {
+		status = domain.ShiftApproved
+	}
+	res, err := db.Exec(
+		`INSERT INTO shifts (employee_id, date, amount, paid, start_time, end_time, break_minutes, hourly_rate, status, currency)
+         VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		shift.EmployeeID,
 		shift.Date.Format("2006-01-02"),
 		shift.Amount,
 		shift.Paid,
+		start, end, breakMinutes, rate, status, shift.Currency.OrDefault(),
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return int(id), err
+}
+
+// shiftColumns — колонки, которые читает scanShift, в том же порядке.
+const shiftColumns = `id, employee_id, date, amount, paid,
+    (SELECT COALESCE(SUM(a.amount), 0) FROM payout_allocations a WHERE a.shift_id = shifts.id),
+    start_time, end_time, break_minutes, hourly_rate, status, currency`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanShift(row rowScanner) (domain.DomainShift, error) {
+	var (
+		s            domain.DomainShift
+		dateStr      string
+		start, end   sql.NullString
+		breakMinutes int
+		rate         sql.NullInt64
+	)
+	if err := row.Scan(&s.ID, &s.EmployeeID, &dateStr, &s.Amount, &s.Paid, &s.PaidAmount,
+		&start, &end, &breakMinutes, &rate, &s.Status, &s.Currency); err != nil {
+		return s, err
+	}
+	var err error
+	s.Date, err = time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return s, err
+	}
+	if start.Valid && end.Valid {
+		h := domain.HourlyShift{BreakMinutes: breakMinutes, Rate: money.FromMinor(rate.Int64)}
+		if h.Start, err = domain.ParseClock(start.String); err != nil {
+			return s, err
+		}
+		if h.End, err = domain.ParseClock(end.String); err != nil {
+			return s, err
+		}
+		s.Hourly = &h
+	}
+	return s, nil
+}
+
+func hourlyColumns(h *domain.HourlyShift) (start, end sql.NullString, breakMinutes int, rate sql.NullInt64) {
+	if h == nil {
+		return
+	}
+	start = sql.NullString{String: domain.FormatClock(h.Start), Valid: true}
+	end = sql.NullString{String: domain.FormatClock(h.End), Valid: true}
+	rate = sql.NullInt64{Int64: h.Rate.Minor(), Valid: true}
+	return start, end, h.BreakMinutes, rate
 }
 
 func (r *SqliteShiftRepo) GetShifts(employeeID int, from, to time.Time) ([]domain.DomainShift, error) {
 	rows, err := r.db.Query(
-		`SELECT id, employee_id, date, amount, paid FROM shifts WHERE employee_id = ? AND date BETWEEN ? AND ?`,
+		`SELECT `+shiftColumns+`
+         FROM shifts WHERE employee_id = ? AND date BETWEEN ? AND ? ORDER BY date, id`,
 		employeeID,
 		from.Format("2006-01-02"),
 		to.Format("2006-01-02"),
@@ -45,32 +136,79 @@ func (r *SqliteShiftRepo) GetShifts(employeeID int, from, to time.Time) ([]domai
 
 	var shifts []domain.DomainShift
 	for rows.Next() {
-		var s domain.DomainShift
-		var dateStr string
-		if err := rows.Scan(&s.ID, &s.EmployeeID, &dateStr, &s.Amount, &s.Paid); err != nil {
-			return nil, err
-		}
-		s.Date, err = time.Parse("2006-01-02", dateStr)
+		s, err := scanShift(rows)
 		if err != nil {
 			return nil, err
 		}
 		shifts = append(shifts, s)
 	}
-	return shifts, nil
+	return shifts, rows.Err()
+}
+
+// GetLastShiftDate возвращает дату последней смены или нулевое время, если смен нет.
+func (r *SqliteShiftRepo) GetLastShiftDate(employeeID int) (time.Time, error) {
+	var dateStr sql.NullString
+	if err := r.db.QueryRow(`SELECT MAX(date) FROM shifts WHERE employee_id = ?`, employeeID).Scan(&dateStr); err != nil {
+		return time.Time{}, err
+	}
+	if !dateStr.Valid {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", dateStr.String)
+}
+
+func (r *SqliteShiftRepo) GetShiftByID(id int) (domain.DomainShift, error) {
+	s, err := scanShift(r.db.QueryRow(`SELECT `+shiftColumns+` FROM shifts WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return s, domain.ErrShiftNotFound
+	}
+	return s, err
 }
 
-func (r *SqliteShiftRepo) MarkShiftsPaid(employeeID int, from, to time.Time) error {
+// UpdateShiftAmount задаёт сумму вручную; почасовые детали при этом
+// сбрасываются, чтобы не противоречить новой сумме.
+func (r *SqliteShiftRepo) UpdateShiftAmount(id int, amount money.Amount) error {
 	_, err := r.db.Exec(
-		`UPDATE shifts SET paid = 1 WHERE employee_id = ? AND date BETWEEN ? AND ?`,
-		employeeID,
-		from.Format("2006-01-02"),
-		to.Format("2006-01-02"),
+		`UPDATE shifts SET amount = ?, start_time = NULL, end_time = NULL, break_minutes = 0, hourly_rate = NULL WHERE id = ?`,
+		amount, id,
 	)
 	return err
 }
 
-func (r *SqliteShiftRepo) UpdateShiftAmount(id int, amount float64) error {
-	_, err := r.db.Exec(`UPDATE shifts SET amount = ? WHERE id = ?`, amount, id)
+func (r *SqliteShiftRepo) UpdateShiftDate(id int, date time.Time) error {
+	_, err := r.db.Exec(`UPDATE shifts SET date = ? WHERE id = ?`, date.Format("2006-01-02"), id)
+	return err
+}
+
+func (r *SqliteShiftRepo) UpdateShiftStatus(id int, status string) error {
+	_, err := r.db.Exec(`UPDATE shifts SET status = ? WHERE id = ?`, status, id)
+	return err
+}
+
+func (r *SqliteShiftRepo) ApproveShifts(ids, payoutIDs []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, id := range ids {
+		if _, err := tx.Exec(`UPDATE shifts SET status = ? WHERE id = ? AND status = ?`,
+			domain.ShiftApproved, id, domain.ShiftPending); err != nil {
+			return err
+		}
+	}
+	for _, id := range payoutIDs {
+		if _, err := tx.Exec(`UPDATE payouts SET status = ? WHERE id = ? AND status = ?`,
+			domain.PayoutConfirmed, id, domain.PayoutUnconfirmed); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *SqliteShiftRepo) DeleteShift(id int) error {
+	_, err := r.db.Exec(`DELETE FROM shifts WHERE id = ?`, id)
 	return err
 }
 
@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"salary-bot/internal/domain"
+)
+
+func TestApproveShifts(t *testing.T) {
+	db := openTestDB(t)
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	shifts, payouts := NewSqliteShiftRepo(db), NewSqlitePayoutRepo(db)
+	ids, payoutIDs, err := shifts.AddShifts([]domain.DomainShift{
+		{EmployeeID: 7, Date: mustDate(t, "2024-03-01"), Amount: 100000, Status: domain.ShiftPending},
+		{EmployeeID: 7, Date: mustDate(t, "2024-03-02"), Amount: 50000, Status: domain.ShiftRejected},
+	}, []domain.BatchPayout{{
+		Payout: domain.Payout{EmployeeID: 7, Amount: 100000, Date: mustDate(t, "2024-03-01"),
+			Note: domain.PayoutNoteImport, RecordedBy: 7, Status: domain.PayoutUnconfirmed, RecordedAt: time.Now()},
+		Shifts: []int{0},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// отклонённую смену ApproveShifts не трогает, даже если её передали
+	if err := shifts.ApproveShifts(ids, payoutIDs); err != nil {
+		t.Fatal(err)
+	}
+	if sh, _ := shifts.GetShiftByID(ids[0]); sh.Status != domain.ShiftApproved || !sh.Paid {
+		t.Errorf("pending shift = %+v", sh)
+	}
+	if sh, _ := shifts.GetShiftByID(ids[1]); sh.Status != domain.ShiftRejected {
+		t.Errorf("rejected shift = %+v", sh)
+	}
+	if p, _ := payouts.GetPayout(payoutIDs[0]); p.Status != domain.PayoutConfirmed {
+		t.Errorf("import payout = %+v", p)
+	}
+}
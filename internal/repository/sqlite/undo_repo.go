@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"salary-bot/internal/domain"
+)
+
+type SqliteUndoRepo struct {
+	db *sql.DB
+}
+
+func NewSqliteUndoRepo(db *sql.DB) *SqliteUndoRepo {
+	return &SqliteUndoRepo{db: db}
+}
+
+func (r *SqliteUndoRepo) AddUndoAction(a domain.UndoAction) (int, error) {
+	res, err := r.db.Exec(
+		`INSERT INTO undo_actions (actor_id, employee_id, kind, ref_id, created_at) VALUES (?, ?, ?, ?, ?)`,
+		a.ActorID, a.EmployeeID, a.Kind, a.RefID, a.CreatedAt.Unix(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return int(id), err
+}
+
+func (r *SqliteUndoRepo) GetLastUndoAction(actorID int64) (domain.UndoAction, bool, error) {
+	var (
+		a         domain.UndoAction
+		createdAt int64
+	)
+	err := r.db.QueryRow(
+		`SELECT id, actor_id, employee_id, kind, ref_id, created_at FROM undo_actions
+         WHERE actor_id = ? ORDER BY id DESC LIMIT 1`,
+		actorID,
+	).Scan(&a.ID, &a.ActorID, &a.EmployeeID, &a.Kind, &a.RefID, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return a, false, nil
+	}
+	if err != nil {
+		return a, false, err
+	}
+	a.CreatedAt = time.Unix(createdAt, 0)
+	return a, true, nil
+}
+
+func (r *SqliteUndoRepo) DeleteUndoAction(id int) error {
+	_, err := r.db.Exec(`DELETE FROM undo_actions WHERE id = ?`, id)
+	return err
+}
+
+func (r *SqliteUndoRepo) DeleteUndoActionsBefore(before time.Time) (int64, error) {
+	res, err := r.db.Exec(`DELETE FROM undo_actions WHERE created_at < ?`, before.Unix())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
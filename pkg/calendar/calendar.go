@@ -4,31 +4,124 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"salary-bot/pkg/i18n"
+
 	"gopkg.in/telebot.v3"
 )
 
+// DateHandler вызывается, когда пользователь выбрал день в календаре.
+type DateHandler func(time.Time, telebot.Context) error
+
+// sessionTTL — сколько живёт неиспользованный календарь.
+const sessionTTL = 24 * time.Hour
+
+type session struct {
+	onDate  DateHandler
+	created time.Time
+}
 
+// CalendarController хранит сессии выбора даты отдельно для каждого чата.
+// ID сессии зашит в callback-данные кнопок, поэтому нажатие в календаре
+// попадает в тот сценарий, который открыл именно это сообщение.
 type CalendarController struct {
-	Bot    *telebot.Bot
-	OnDate func(time.Time, telebot.Context) error
+	Bot *telebot.Bot
+	// Lang — язык календаря для пользователя; nil — i18n.Default.
+	Lang func(telebot.Context) i18n.Lang
+	// Now — текущее время в поясе пользователя, по нему открывается
+	// текущий месяц; nil — время сервера.
+	Now func(telebot.Context) time.Time
+
+	mu       sync.Mutex
+	sessions map[int64]map[string]session
+	seq      uint64
+}
+
+// ShowCalendar открывает новую сессию выбора даты для текущего чата.
+func (cc *CalendarController) ShowCalendar(c telebot.Context, onDate DateHandler) error {
+	sid := cc.open(c.Chat().ID, onDate)
+	now := time.Now()
+	if cc.Now != nil {
+		now = cc.Now(c)
+	}
+	return SendCalendar(c, cc.lang(c), sid, now.Year(), int(now.Month()))
 }
 
+func (cc *CalendarController) lang(c telebot.Context) i18n.Lang {
+	if cc.Lang == nil {
+		return i18n.Default
+	}
+	return cc.Lang(c)
+}
 
-func (cc *CalendarController) ShowCalendar(c telebot.Context) error {
+func (cc *CalendarController) open(chatID int64, onDate DateHandler) string {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.sessions == nil {
+		cc.sessions = make(map[int64]map[string]session)
+	}
 	now := time.Now()
-	return SendCalendar(c, now.Year(), int(now.Month()))
+	for chat, byID := range cc.sessions {
+		for id, s := range byID {
+			if now.Sub(s.created) > sessionTTL {
+				delete(byID, id)
+			}
+		}
+		if len(byID) == 0 {
+			delete(cc.sessions, chat)
+		}
+	}
+	cc.seq++
+	sid := strconv.FormatUint(cc.seq, 36)
+	if cc.sessions[chatID] == nil {
+		cc.sessions[chatID] = make(map[string]session)
+	}
+	cc.sessions[chatID][sid] = session{onDate: onDate, created: now}
+	return sid
 }
 
+// take забирает сессию: выбор даты одноразовый.
+func (cc *CalendarController) take(chatID int64, sid string) (DateHandler, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	s, ok := cc.sessions[chatID][sid]
+	if !ok {
+		return nil, false
+	}
+	delete(cc.sessions[chatID], sid)
+	return s.onDate, true
+}
+
+func (cc *CalendarController) has(chatID int64, sid string) bool {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	_, ok := cc.sessions[chatID][sid]
+	return ok
+}
 
-func SendCalendar(c telebot.Context, year, month int) error {
+// SendCalendar показывает месяц сеткой по неделям с понедельника.
+// Заголовки дней и пустые клетки — кнопки cal_ignore без действия.
+func SendCalendar(c telebot.Context, lang i18n.Lang, sid string, year, month int) error {
 	markup := &telebot.ReplyMarkup{}
-	days := daysInMonth(year, month)
-	var rows []telebot.Row
+	blank := func(text string) telebot.Btn {
+		return markup.Data(text, "cal_ignore", sid)
+	}
+	header := telebot.Row{}
+	for _, wd := range lang.Weekdays() {
+		header = append(header, blank(wd))
+	}
+	rows := []telebot.Row{header}
 	week := telebot.Row{}
+	// Weekday считает с воскресенья, сетка — с понедельника
+	offset := (int(time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC).Weekday()) + 6) % 7
+	for i := 0; i < offset; i++ {
+		week = append(week, blank(" "))
+	}
+	days := daysInMonth(year, month)
 	for d := 1; d <= days; d++ {
-		btn := markup.Data(strconv.Itoa(d), "cal_day", strconv.Itoa(d)+"-"+strconv.Itoa(month)+"-"+strconv.Itoa(year))
+		btn := markup.Data(strconv.Itoa(d), "cal_day", sid+"-"+strconv.Itoa(d)+"-"+strconv.Itoa(month)+"-"+strconv.Itoa(year))
 		week = append(week, btn)
 		if len(week) == 7 {
 			rows = append(rows, week)
@@ -36,100 +129,84 @@ func SendCalendar(c telebot.Context, year, month int) error {
 		}
 	}
 	if len(week) > 0 {
+		for len(week) < 7 {
+			week = append(week, blank(" "))
+		}
 		rows = append(rows, week)
 	}
-	prev := markup.Data("<", "cal_prev", strconv.Itoa(month-1)+"-"+strconv.Itoa(year))
-	next := markup.Data(">", "cal_next", strconv.Itoa(month+1)+"-"+strconv.Itoa(year))
+	prev := markup.Data("<", "cal_prev", sid+"-"+strconv.Itoa(month-1)+"-"+strconv.Itoa(year))
+	next := markup.Data(">", "cal_next", sid+"-"+strconv.Itoa(month+1)+"-"+strconv.Itoa(year))
 	rows = append(rows, telebot.Row{prev, next})
 	markup.Inline(rows...)
-	ruMonths := map[time.Month]string{
-		time.January:   "Январь",
-		time.February:  "Февраль",
-		time.March:     "Март",
-		time.April:     "Апрель",
-		time.May:       "Май",
-		time.June:      "Июнь",
-		time.July:      "Июль",
-		time.August:    "Август",
-		time.September: "Сентябрь",
-		time.October:   "Октябрь",
-		time.November:  "Ноябрь",
-		time.December:  "Декабрь",
-	}
-	monthName := time.Month(month).String()
-	if ru, ok := ruMonths[time.Month(month)]; ok {
-		monthName = ru
-	}
-	title := "Выберите дату: " + monthName + " " + strconv.Itoa(year)
+	title := lang.T("cal.pick", lang.Month(time.Month(month)), year)
 	if c.Callback() != nil {
 		return c.Edit(title, markup)
 	}
 	return c.Send(title, markup)
 }
 
+// HandleCallback обрабатывает cal_day / cal_prev / cal_next; cal_ignore
+// (заголовки и пустые клетки) ничего не делает.
+func (cc *CalendarController) HandleCallback(c telebot.Context) error {
+	if c.Callback() == nil {
+		return nil
+	}
+	raw := c.Data()
+	raw = strings.TrimPrefix(raw, "\f")
+	split := strings.SplitN(raw, "|", 2)
+	if len(split) != 2 {
+		return nil
+	}
+	parts := SplitDateData(split[1])
+	if len(parts) == 0 {
+		return nil
+	}
+	sid, parts := parts[0], parts[1:]
+	chatID := c.Chat().ID
+	lang := cc.lang(c)
 
-func (cc *CalendarController) RegisterHandlers() {
-	cc.Bot.Handle(telebot.OnCallback, func(c telebot.Context) error {
-		if c.Callback() == nil {
-			return nil
+	switch split[0] {
+	case "cal_day":
+		if len(parts) != 3 {
+			return c.Send(lang.T("cal.bad_date"), &telebot.ReplyMarkup{})
 		}
-		raw := c.Data()
-		raw = strings.TrimPrefix(raw, "\f")
-		split := strings.SplitN(raw, "|", 2)
-		if len(split) != 2 {
-			return nil
+		day, _ := strconv.Atoi(parts[0])
+		month, _ := strconv.Atoi(parts[1])
+		year, _ := strconv.Atoi(parts[2])
+		date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		onDate, ok := cc.take(chatID, sid)
+		if !ok {
+			return c.Send(lang.T("cal.stale"), &telebot.ReplyMarkup{})
 		}
-		payload := split[1]
-		
-		if split[0] == "cal_day" {
-			
-			parts := SplitDateData(payload)
-			if len(parts) != 3 {
-				return c.Send("Ошибка даты", &telebot.ReplyMarkup{})
-			}
-			day, _ := strconv.Atoi(parts[0])
-			month, _ := strconv.Atoi(parts[1])
-			year, _ := strconv.Atoi(parts[2])
-			date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
-			if cc.OnDate != nil {
-				return cc.OnDate(date, c)
-			}
-			return c.Send("Ошибка даты", &telebot.ReplyMarkup{})
+		return onDate(date, c)
+	case "cal_prev", "cal_next":
+		log.Printf("[calendar] %s chat=%d payload=%s", split[0], chatID, split[1])
+		if len(parts) != 2 {
+			return c.Send(lang.T("cal.bad_month"), &telebot.ReplyMarkup{})
 		}
-		
-		if split[0] == "cal_prev" {
-			log.Println("[calendar] cal_prev callback received, payload:", payload)
-			parts := SplitDateData(payload)
-			if len(parts) != 2 {
-				return c.Send("Ошибка месяца", &telebot.ReplyMarkup{})
-			}
-			month, _ := strconv.Atoi(parts[0])
-			year, _ := strconv.Atoi(parts[1])
-			if month < 1 {
-				month = 12
-				year--
-			}
-			return SendCalendar(c, year, month)
+		if !cc.has(chatID, sid) {
+			return c.Send(lang.T("cal.stale"), &telebot.ReplyMarkup{})
 		}
-		
-		if split[0] == "cal_next" {
-			
-			parts := SplitDateData(payload)
-			if len(parts) != 2 {
-				return c.Send("Ошибка месяца", &telebot.ReplyMarkup{})
-			}
-			month, _ := strconv.Atoi(parts[0])
-			year, _ := strconv.Atoi(parts[1])
-			if month > 12 {
-				month = 1
-				year++
-			}
-			return SendCalendar(c, year, month)
+		month, _ := strconv.Atoi(parts[0])
+		year, _ := strconv.Atoi(parts[1])
+		if month < 1 {
+			month = 12
+			year--
 		}
-		return nil
-	})
+		if month > 12 {
+			month = 1
+			year++
+		}
+		return SendCalendar(c, lang, sid, year, month)
+	}
+	return nil
 }
 
+// RegisterHandlers подключает календарь к боту напрямую, если у бота
+// нет собственного роутера callback-ов.
+func (cc *CalendarController) RegisterHandlers() {
+	cc.Bot.Handle(telebot.OnCallback, cc.HandleCallback)
+}
 
 func SplitDateData(data string) []string {
 	return strings.Split(data, "-")
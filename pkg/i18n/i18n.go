@@ -0,0 +1,205 @@
+// Package i18n — каталог сообщений бота и правила их оформления для
+// каждого языка: множественное число, названия месяцев и дней недели,
+// формат дат и сумм.
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"salary-bot/pkg/money"
+)
+
+// Lang — код языка ISO 639-1.
+type Lang string
+
+const (
+	RU Lang = "ru"
+	EN Lang = "en"
+)
+
+// Default — язык сообщений, если другой не выбран и не подошёл язык Telegram.
+const Default = RU
+
+var ErrUnknownLang = errors.New("неизвестный язык")
+
+type language struct {
+	name     string
+	messages map[string]string
+	// plural возвращает номер формы из записи "форма|форма|..." для числа n.
+	plural      func(n int) int
+	months      [12]string
+	monthsShort [12]string
+	// weekdays — короткие названия дней с понедельника.
+	weekdays   [7]string
+	dateLayout string
+	// dayMonthLayout — дата без года для списков и кнопок.
+	dayMonthLayout string
+	decimal        string
+	group          string
+}
+
+var languages = map[Lang]*language{
+	RU: {
+		name:     "Русский",
+		messages: ru,
+		plural: func(n int) int {
+			n %= 100
+			switch {
+			case n%10 == 1 && n != 11:
+				return 0
+			case n%10 >= 2 && n%10 <= 4 && (n < 12 || n > 14):
+				return 1
+			}
+			return 2
+		},
+		months:         [12]string{"Январь", "Февраль", "Март", "Апрель", "Май", "Июнь", "Июль", "Август", "Сентябрь", "Октябрь", "Ноябрь", "Декабрь"},
+		monthsShort:    [12]string{"Янв", "Фев", "Мар", "Апр", "Май", "Июн", "Июл", "Авг", "Сен", "Окт", "Ноя", "Дек"},
+		weekdays:       [7]string{"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"},
+		dateLayout:     "02.01.2006",
+		dayMonthLayout: "02.01",
+		// как в остальных сообщениях и выгрузках: 1500.00
+		decimal: ".",
+	},
+	EN: {
+		name:     "English",
+		messages: en,
+		plural: func(n int) int {
+			if n == 1 {
+				return 0
+			}
+			return 1
+		},
+		months:         [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		monthsShort:    [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		weekdays:       [7]string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"},
+		dateLayout:     "2006-01-02",
+		dayMonthLayout: "Jan 2",
+		decimal:        ".",
+		group:          ",",
+	},
+}
+
+// Langs возвращает поддерживаемые языки.
+func Langs() []Lang {
+	return []Lang{RU, EN}
+}
+
+// Parse разбирает код языка: "en", "EN", "en-US".
+func Parse(code string) (Lang, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	if _, ok := languages[Lang(code)]; !ok {
+		return "", ErrUnknownLang
+	}
+	return Lang(code), nil
+}
+
+// Match выбирает язык по language_code из Telegram, а для неподдерживаемых
+// языков возвращает fallback.
+func Match(code string, fallback Lang) Lang {
+	if l, err := Parse(code); err == nil {
+		return l
+	}
+	return fallback.OrDefault()
+}
+
+func (l Lang) OrDefault() Lang {
+	if _, ok := languages[l]; !ok {
+		return Default
+	}
+	return l
+}
+
+func (l Lang) lang() *language {
+	return languages[l.OrDefault()]
+}
+
+// Name — название языка на нём самом.
+func (l Lang) Name() string {
+	return l.lang().name
+}
+
+// T возвращает сообщение key, подставляя args как в fmt.Sprintf.
+// Сообщения без перевода берутся из языка по умолчанию.
+func (l Lang) T(key string, args ...any) string {
+	msg, ok := l.lang().messages[key]
+	if !ok {
+		if msg, ok = languages[Default].messages[key]; !ok {
+			log.Printf("[i18n] missing message %q", key)
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// N возвращает форму сообщения key для числа n: "%d смена|%d смены|%d смен".
+func (l Lang) N(key string, n int) string {
+	forms := strings.Split(l.T(key), "|")
+	i := l.lang().plural(n)
+	if i >= len(forms) {
+		i = len(forms) - 1
+	}
+	return fmt.Sprintf(forms[i], n)
+}
+
+// Is сообщает, совпадает ли text с сообщением key на любом языке — для
+// кнопок reply-клавиатуры, оставшихся от прежнего языка.
+func Is(text, key string) bool {
+	for _, lang := range languages {
+		if msg, ok := lang.messages[key]; ok && msg == text {
+			return true
+		}
+	}
+	return false
+}
+
+// Has сообщает, есть ли сообщение key в каталоге. Полноту переводов
+// проверяет TestCatalogs, поэтому достаточно языка по умолчанию.
+func Has(key string) bool {
+	_, ok := languages[Default].messages[key]
+	return ok
+}
+
+func (l Lang) Month(m time.Month) string {
+	return l.lang().months[m-1]
+}
+
+func (l Lang) MonthShort(m time.Month) string {
+	return l.lang().monthsShort[m-1]
+}
+
+// Weekdays — короткие названия дней недели начиная с понедельника.
+func (l Lang) Weekdays() [7]string {
+	return l.lang().weekdays
+}
+
+func (l Lang) Date(t time.Time) string {
+	return t.Format(l.lang().dateLayout)
+}
+
+// DayMonth — дата без года: "05.03", "Mar 5".
+func (l Lang) DayMonth(t time.Time) string {
+	return t.Format(l.lang().dayMonthLayout)
+}
+
+// Number пишет сумму без валюты с разделителями языка.
+func (l Lang) Number(a money.Amount) string {
+	return a.Format(l.lang().decimal, l.lang().group)
+}
+
+func (l Lang) Money(c money.Currency, a money.Amount) string {
+	return c.FormatWith(a, l.Number)
+}
+
+func (l Lang) Totals(t money.Totals) string {
+	return t.FormatWith(l.Number)
+}
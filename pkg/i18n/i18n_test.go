@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestN(t *testing.T) {
+	tests := []struct {
+		lang Lang
+		n    int
+		want string
+	}{
+		{RU, 0, "0 смен"},
+		{RU, 1, "1 смена"},
+		{RU, 2, "2 смены"},
+		{RU, 4, "4 смены"},
+		{RU, 5, "5 смен"},
+		{RU, 11, "11 смен"},
+		{RU, 12, "12 смен"},
+		{RU, 13, "13 смен"},
+		{RU, 14, "14 смен"},
+		{RU, 21, "21 смена"},
+		{RU, 22, "22 смены"},
+		{RU, 25, "25 смен"},
+		{RU, 101, "101 смена"},
+		{RU, 111, "111 смен"},
+		{RU, 112, "112 смен"},
+		{RU, 1021, "1021 смена"},
+		{EN, 0, "0 shifts"},
+		{EN, 1, "1 shift"},
+		{EN, 2, "2 shifts"},
+		{EN, 11, "11 shifts"},
+		{EN, 21, "21 shifts"},
+		{EN, 101, "101 shifts"},
+		// неизвестный язык — язык по умолчанию
+		{"de", 2, "2 смены"},
+	}
+	for _, tt := range tests {
+		if got := tt.lang.N("shifts", tt.n); got != tt.want {
+			t.Errorf("%s.N(shifts, %d) = %q, want %q", tt.lang, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestNFewerForms(t *testing.T) {
+	// запись с одной формой подходит для любого числа
+	languages[RU].messages["test.single"] = "%d шт."
+	defer delete(languages[RU].messages, "test.single")
+	for _, n := range []int{1, 3, 5} {
+		if got := RU.N("test.single", n); !strings.HasSuffix(got, " шт.") {
+			t.Errorf("N(%d) = %q", n, got)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := EN.T("err.generic", "boom"); got != "Error: boom" {
+		t.Errorf("EN.T = %q", got)
+	}
+	if got := RU.T("no.such.key"); got != "no.such.key" {
+		t.Errorf("missing key = %q", got)
+	}
+}
+
+func TestParseAndMatch(t *testing.T) {
+	for code, want := range map[string]Lang{"en": EN, "EN": EN, "en-US": EN, " ru ": RU, "ru_RU": RU} {
+		if got, err := Parse(code); err != nil || got != want {
+			t.Errorf("Parse(%q) = %q, %v", code, got, err)
+		}
+	}
+	if _, err := Parse("de"); err != ErrUnknownLang {
+		t.Errorf("Parse(de) err = %v", err)
+	}
+	if got := Match("de", EN); got != EN {
+		t.Errorf("Match(de, en) = %q", got)
+	}
+	if got := Match("", "xx"); got != Default {
+		t.Errorf("Match with unknown fallback = %q", got)
+	}
+}
+
+var verbRe = regexp.MustCompile(`%[-+# 0]*\d*(?:\.\d+)?[a-zA-Z%]`)
+
+// TestCatalogs проверяет, что у каждого сообщения есть перевод с теми же
+// подстановками и тем же числом форм, что требует язык.
+func TestCatalogs(t *testing.T) {
+	forms := map[Lang]int{RU: 3, EN: 2}
+	for key, msg := range ru {
+		other, ok := en[key]
+		if !ok {
+			t.Errorf("en: no %q", key)
+			continue
+		}
+		ruForms, enForms := strings.Split(msg, "|"), strings.Split(other, "|")
+		if len(ruForms) > 1 || len(enForms) > 1 {
+			if len(ruForms) != forms[RU] || len(enForms) != forms[EN] {
+				t.Errorf("%q: %d ru and %d en plural forms", key, len(ruForms), len(enForms))
+			}
+			continue
+		}
+		if a, b := verbRe.FindAllString(msg, -1), verbRe.FindAllString(other, -1); strings.Join(a, " ") != strings.Join(b, " ") {
+			t.Errorf("%q: ru verbs %v, en verbs %v", key, a, b)
+		}
+	}
+	for key := range en {
+		if _, ok := ru[key]; !ok {
+			t.Errorf("ru: no %q", key)
+		}
+	}
+}
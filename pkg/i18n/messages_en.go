@@ -0,0 +1,395 @@
+package i18n
+
+// Plural forms are separated by "|": one, other.
+var en = map[string]string{
+	// menu and common buttons
+	"menu.add_shift":     "➕ Add shift",
+	"menu.salary":        "💰 Salary",
+	"menu.payout":        "💸 Payout",
+	"menu.choose":        "Choose an action:",
+	"btn.cancel":         "❌ Cancel",
+	"btn.today":          "📅 Today",
+	"btn.other_date":     "📆 Another date",
+	"btn.other_month":    "📊 Another month",
+	"btn.range":          "🗓️ Date range",
+	"btn.payout_history": "🧾 Payout history",
+	"btn.payslip":        "📄 Payslip",
+	"btn.payout_all":     "✅ Pay everything",
+	"btn.payout_all_in":  "✅ All in %s: %s",
+	"btn.at_rate":        "✅ At rate %s",
+	"btn.reset_yes":      "✅ Yes, delete",
+	"btn.my_data":        "🙋 My data",
+	"cancelled":          "Cancelled.",
+	"cancelled.menu":     "Cancelled. Choose a command from the menu.",
+
+	// errors
+	"err.generic":   "Error: %s",
+	"err.data":      "Failed to load data: %s",
+	"err.salary":    "Failed to calculate salary: %s",
+	"err.shifts":    "Failed to load shifts: %s",
+	"err.payouts":   "Failed to load payouts: %s",
+	"err.employees": "Failed to load employees: %s",
+	"err.date":      "Invalid date, please start over.",
+	"err.save":      "Failed to save: %s",
+
+	// shifts
+	"shift.is_today":        "Is this today's shift?",
+	"shift.ask_amount":      "Enter the amount for the shift on %s:",
+	"shift.ask_amount_rate": "Enter the amount for the shift on %s (rate: %s):",
+	"shift.amount_invalid":  "Invalid amount. Please try again.",
+	"shift.amount_min":      "The amount must be at least 1. Enter the amount again.",
+	"shift.add_failed":      "Failed to add the shift: %s",
+	"shift.added":           "Shift added!",
+	"shifts":                "%d shift|%d shifts",
+
+	// salary
+	"salary.this_month":  "Salary this month: %s",
+	"salary.month":       "Salary for %s: %s",
+	"salary.unpaid":      "Unpaid in total: %s",
+	"salary.pending":     "⏳ Awaiting approval: %s",
+	"salary.unconfirmed": "⏳ Unconfirmed payouts: %s",
+	"salary.disputed":    "❗ Disputed payouts: %s",
+	"range.pick_start":   "Pick the start date of the range",
+	"range.pick_end":     "Start: %s\nNow pick the end date",
+	"range.total":        "Earned from %s to %s: %s",
+	"month.pick":         "Pick a month: %d",
+
+	// payouts
+	"payout.ask":              "How much to pay? Enter an amount, press 'Pay everything' or type 'cancel' to exit.",
+	"payout.amount_min":       "The payout must be at least 1. Enter the amount again.",
+	"payout.unknown_currency": "Unknown currency. Available: %s. Enter the amount again.",
+	"payout.no_currency":      "There are no unpaid shifts in %s. Available to pay: %s. Specify the currency, e.g. 100 %s",
+	"payout.too_much":         "You cannot pay more than was earned. Available to pay: %s",
+	"payout.failed":           "Payout failed: %s",
+	"payout.all_failed":       "Full payout failed: %s",
+	"payout.done":             "Payout of %s recorded!",
+	"payout.all_done":         "Everything is paid!",
+	"payouts.none":            "No payouts yet.",
+	"payouts.recent":          "Recent payouts:",
+
+	// notifications to an employee about a manager's actions
+	"notify.by_manager":  "Manager %s: %s",
+	"notify.shift_added": "added a shift on %s for %s",
+	"notify.payout":      "recorded a payout of %s",
+
+	// employees
+	"employees.none":       "No employees yet.",
+	"employees.header":     "Employees:",
+	"employees.line":       "• %s — unpaid %s, %s",
+	"employees.no_shifts":  "no shifts",
+	"employees.last_shift": "last shift %s",
+	"employees.hint":       "Pick an employee to add their shifts, view their salary and record payouts.",
+	"employees.selected":   "Currently selected: %s",
+
+	// /resetme
+	"reset.ask":    "Delete all your shifts and payouts? This cannot be undone.",
+	"reset.hint":   "To confirm, press the button or run: /resetme confirm",
+	"reset.done":   "Your data has been deleted.",
+	"reset.failed": "Failed to reset data: %s",
+
+	// /import
+	"import.help": "Send a CSV file with shifts as a document.\n" +
+		"Columns: date, amount and (optionally) paid — yes/no. " +
+		"A header like date,amount,paid is allowed.\n" +
+		"Dates: 2024-03-01 or 01.03.2024. A file from /export works too.",
+	"import.only_csv":        "I only accept CSV files with shifts. See /import",
+	"import.too_big":         "The file is too large, 1 MB at most.",
+	"import.download_failed": "Failed to download the file: %s",
+	"import.parse_failed":    "Failed to parse the file: %s",
+	"import.header":          "Shift import:",
+	"import.valid":           "• to add: %d",
+	"import.duplicates":      "• duplicates (skipped): %d",
+	"import.errors":          "• errors (skipped): %d",
+	"import.line_error":      "Line %d: %s",
+	"import.line_duplicate":  "Line %d: a shift on %s for %s already exists",
+	"import.nothing":         "Nothing to add.",
+	"import.confirm":         "✅ Import %s",
+	"import.stale":           "This import has expired, send the file again.",
+	"import.failed":          "Import failed: %s",
+	"import.rolled_back":     "Import failed, nothing was added: %s",
+	"import.done":            "Imported: %s",
+
+	// /lang
+	"lang.current":  "Language: %s.\nChange: /lang en, /lang ru or /lang auto to follow Telegram.",
+	"lang.auto":     "Language: %s (from Telegram).\nChange: /lang en or /lang ru.",
+	"lang.set":      "Done, language: %s.",
+	"lang.set_auto": "Done, following the Telegram language — %s.",
+	"lang.unknown":  "Unknown language. Available: %s, auto.",
+
+	// time zone
+	"tz.ask": "Which time zone are you in? It decides what \"today\" is and when reminders arrive.\n" +
+		"Share your location with the button below or type an offset from UTC (+3) or a zone name (Europe/London).",
+	"tz.btn_location": "📍 Share location",
+	"tz.btn_skip":     "Skip",
+	"tz.current":      "Time zone: %s, the time is %s.",
+	"tz.set":          "Done, time zone: %s, the time is %s.",
+	"tz.skipped":      "OK, the server time is used for now. Set a zone: /timezone",
+	"tz.invalid":      "Could not recognise the zone. Type an offset from UTC in hours, e.g. +3 or -5, or a name like Europe/London.",
+
+	// calendar
+	"cal.pick":      "Pick a date: %s %d",
+	"cal.bad_date":  "Invalid date",
+	"cal.bad_month": "Invalid month",
+	"cal.stale":     "This calendar has expired, open it again.",
+
+	// common buttons and errors
+	"btn.back":      "⬅️ Back",
+	"err.rate":      "Failed to get the rate: %s",
+	"err.rate_save": "Failed to save the rate: %s",
+	"err.history":   "Failed to get the history: %s",
+	"step.done":     "This step is already finished. Start again: “%s”.",
+
+	// /shifts
+	"shifts.title":            "Shifts for %s:",
+	"shifts.none":             "No shifts in %s.",
+	"shifts.card":             "Shift %s\nAmount: %s",
+	"shifts.card_hourly":      "Time: %s–%s, break %d min, %s × %s",
+	"shifts.card_pending":     "⏳ Awaiting manager approval",
+	"shifts.card_rejected":    "❌ Rejected by a manager, not counted in salary",
+	"shifts.card_paid":        "Paid: %s\n\nA paid shift cannot be changed — reverse the payout first.",
+	"shifts.btn_amount":       "✏️ Amount",
+	"shifts.btn_date":         "📅 Date",
+	"shifts.btn_delete":       "🗑 Delete",
+	"shifts.btn_delete_yes":   "🗑 Yes, delete",
+	"shifts.btn_reverse":      "↩️ Reverse payout #%d (%s)",
+	"shifts.btn_dispute":      "❗ Dispute payout #%d (%s)",
+	"shifts.btn_to_list":      "⬅️ Back to list",
+	"shifts.edit_amount":      "Enter a new amount for the %s shift (now %s):",
+	"shifts.delete_ask":       "Delete the %s shift for %s?",
+	"shifts.deleted":          "The %s shift has been deleted.",
+	"shifts.restart":          "Something went wrong, start again: /shifts",
+	"shifts.not_found":        "Shift not found.",
+	"shifts.paid":             "The shift is already paid. Reverse the payout in the shift card first.",
+	"shifts.approved_locked":  "The shift has been approved by a manager. Only a manager can change or delete it.",
+	"shifts.card_approved":    "\nThe shift has been approved by a manager — only a manager can change it.",
+	"payout.not_found":        "Payout not found.",
+	"payout.btn_reverse_yes":  "↩️ Yes, reverse the payout",
+	"payout.reverse_ask":      "Reverse payout #%d of %s for %s? All shifts it covered will become unpaid again.",
+	"payout.reversed":         "Payout #%d for %s has been reversed. You can now edit the shifts in /shifts.",
+	"payout.btn_dispute_yes":  "❗ Yes, I did not receive it",
+	"payout.dispute_ask":      "Payout #%d of %s for %s was recorded by a manager and cannot be deleted. Dispute it — you did not receive it?",
+	"payout.confirmed_locked": "The payout has been confirmed by both sides and cannot be reversed. If this is a mistake, contact a manager.",
+
+	// rates
+	"rate.none":              "No rate set. Set one: /rate 2500 or /rate 2500 01.09.2025",
+	"rate.current_shift":     "Current rate: %s per shift",
+	"rate.current_hour":      "Current rate: %s per hour",
+	"rate.role_manager_only": "Only a manager can set role rates.",
+	"rate.role_usage":        "Example: /rate role employee 2500 01.09.2025",
+	"rate.unknown_role":      "Unknown role: %s",
+	"rate.role_invalid":      "Invalid rate or date. Example: /rate role employee 2500 01.09.2025",
+	"rate.invalid":           "Invalid rate or date. Example: /rate 2500 01.09.2025",
+	"rate.role_set":          "Rate for role %s: %s from %s",
+	"rate.set":               "Rate %s applies from %s",
+	"rate.not_set_enter":     "No rate for this date, enter the amount.",
+	"shift.added_at_rate":    "Shift added! At the rate of %s",
+
+	// hourly pay
+	"hourly.current":        "Hourly pay, rate %s per hour.\nTurn off: /hourly off",
+	"hourly.per_shift":      "Pay per shift. Switch to hourly: /hourly <rate per hour>, e.g. /hourly 350",
+	"hourly.disabled":       "Done: pay per shift.",
+	"hourly.invalid":        "Invalid rate. Example: /hourly 350",
+	"hourly.enabled":        "Done: hourly pay, %s per hour. When you add a shift, the bot will ask for its start and end time.",
+	"hourly.ask_times":      "Enter the time of the %s shift as 09:00-18:00.\nIf there was a break, add its minutes after a space: 09:00-18:00 60",
+	"hourly.times_invalid":  "Could not read the time. Example: 09:00-18:00 or 22:00-06:00 30",
+	"hourly.no_rate":        "No hourly rate set: /hourly <rate> or /rate <rate>.",
+	"hourly.break_too_long": "The break cannot be longer than the shift. Enter the time again.",
+	"hourly.added":          "Shift added! %s × %s = %s",
+	"worked.hours":          "%d h",
+	"worked.hours_minutes":  "%d h %d min",
+
+	// evening question
+	"remind.off":        "The evening shift question is off. Turn it on: /remind 20:30",
+	"remind.current":    "I ask about your shift every day at %s. Turn off: /remind off",
+	"remind.invalid":    "Invalid time. Example: /remind 20:30",
+	"remind.disabled":   "The evening shift question is turned off.",
+	"remind.set":        "I will ask about your shift every day at %s.",
+	"evening.ask":       "Did you work today?",
+	"evening.btn_rate":  "Yes, at my rate",
+	"evening.btn_other": "Yes, another amount",
+	"evening.btn_no":    "No",
+	"evening.no_rate":   "No rate set (/rate).",
+	"evening.added":     "The %s shift has been added at the rate of %s",
+	"evening.shift_for": "Shift for %s",
+	"evening.rest":      "OK, have a good rest!",
+
+	// /undo
+	"undo.btn":         "↩️ Undo",
+	"undo.payout_done": "The payout has been undone.",
+	"undo.shift_done":  "The shift has been removed.",
+	"undo.nothing":     "Nothing to undo.",
+	"undo.expired":     "The undo window has passed. You can fix the shift in /shifts.",
+	"undo.not_latest":  "Only the latest action can be undone: /undo",
+	"undo.shift_paid":  "The shift is already paid. Reverse the payout first.",
+	"undo.failed":      "Failed to undo: %s",
+
+	// /currency
+	"currency.current": "Pay currency: %s.\nChange: /currency <code>, available: %s",
+	"currency.unknown": "Unknown currency. Available: %s",
+	"currency.set":     "Done: new shifts are recorded in %s. Earlier shifts and payouts keep their currency.",
+
+	// /history
+	"history.none":           "No changes yet.",
+	"history.header":         "Recent changes:",
+	"audit.shift_created":    "added the %s shift for %s",
+	"audit.shift_updated":    "changed the %s shift: %s",
+	"audit.date_changed":     "date %s → %s",
+	"audit.amount_changed":   "amount %s → %s",
+	"audit.approved":         "approved",
+	"audit.rejected":         "rejected",
+	"audit.pending":          "awaiting approval",
+	"audit.shift_deleted":    "deleted the %s shift for %s",
+	"audit.shifts_deleted":   "deleted all shifts",
+	"audit.payout_created":   "payout #%d for %s",
+	"audit.payout_confirmed": "payout #%d for %s confirmed",
+	"audit.payout_disputed":  "payout #%d for %s disputed",
+	"audit.payout_deleted":   "reversed payout #%d for %s",
+	"audit.payouts_deleted":  "deleted all payouts",
+	"audit.employee_created": "employee registered",
+	"audit.employee_updated": "employee profile changed",
+	"audit.rate":             "rate %s from %s",
+
+	// acting for an employee
+	"act.failed":  "Could not select the employee: %s",
+	"act.self":    "You are working with your own data again.",
+	"act.other":   "“%s”, “%s” and “%s” now work with the data of %s. Back to yours: /employees → “%s”.",
+	"auth.denied": "⛔ Access denied.",
+
+	// shift approval
+	"approval.request":         "Shift to approve\n👤 %s\nDate: %s\nAmount: %s",
+	"approval.btn_approve":     "✅ Approve",
+	"approval.btn_reject":      "❌ Reject",
+	"approval.btn_approve_all": "✅ Approve all",
+	"approval.pending":         "⏳ The shift is awaiting manager approval.",
+	"approval.import_request":  "👤 %s imported %s\nAwaiting approval: %s",
+	"approval.import_pending":  "⏳ The shifts are awaiting manager approval.",
+	"approval.reviewed":        "The shift has already been reviewed or deleted.",
+	"approval.approved":        "approved ✅",
+	"approval.rejected":        "rejected ❌",
+	"approval.shift":           "The %s shift for %s is %s",
+	"approval.approved_n":      "Shifts approved: %d",
+	"notify.shift_reviewed":    "the %s shift for %s is %s",
+	"notify.approved_n":        "shifts approved: %d",
+
+	// payout confirmation
+	"payout.awaits_manager":   "⏳ The payout is awaiting manager confirmation.",
+	"payout.awaits_employee":  "⏳ The payout is awaiting the employee's confirmation.",
+	"payout.btn_received":     "✅ Received",
+	"payout.btn_not_received": "❌ Not received",
+	"payout.btn_paid":         "✅ Paid",
+	"payout.btn_not_paid":     "❌ Not paid",
+	"payout.ask_employee":     "Manager %s recorded payout #%d of %s for %s. Did you receive it?",
+	"payout.ask_manager":      "👤 %s recorded payout #%d of %s for %s. Please confirm it was made.",
+	"payout.reminder":         "⚠️ Reminder. ",
+	"payout.not_confirmed":    "⚠️ %s has not confirmed payout #%d of %s for %s.",
+	"payout.already_reviewed": "The payout has already been confirmed, disputed or reversed.",
+	"payout.confirmed":        "Payout #%d of %s for %s confirmed ✅",
+	"payout.disputed":         "Payout #%d of %s for %s disputed ❌\nThe shifts it covered count as unpaid again.",
+
+	// /export
+	"export.title":      "Export. %s",
+	"export.pick_start": "Pick the first date of the export",
+	"export.format":     "Export for %s – %s. Choose a format:",
+	"export.preparing":  "Preparing the export for %s – %s…",
+	"export.failed":     "Failed to prepare the export: %s",
+
+	// payslip
+	"payslip.title":  "Payslip. %s",
+	"payslip.for":    "Payslip for %s:",
+	"payslip.failed": "Failed to prepare the payslip: %s",
+
+	// payday reminder
+	"reminder.today":      "Today, %s, is payday.",
+	"reminder.missed":     "%s was payday.",
+	"reminder.unpaid":     "Unpaid: %s",
+	"reminder.btn_payout": "💸 Record a payout",
+
+	// payslip HTML
+	"payslip.doc_title":     "Payslip — %s",
+	"payslip.heading":       "Payslip for %s",
+	"payslip.shifts":        "Shifts",
+	"payslip.col_date":      "Date",
+	"payslip.col_time":      "Time",
+	"payslip.col_amount":    "Amount",
+	"payslip.col_paid":      "Paid",
+	"payslip.break":         ", break %d min",
+	"payslip.no_shifts":     "No shifts this month.",
+	"payslip.adjustments":   "Adjustments",
+	"payslip.col_shift":     "Shift",
+	"payslip.col_change":    "Change",
+	"payslip.col_when":      "When",
+	"payslip.payouts":       "Payouts",
+	"payslip.col_no":        "#",
+	"payslip.col_note":      "Note",
+	"payslip.no_payouts":    "No payouts this month.",
+	"payslip.totals":        "Totals",
+	"payslip.opening":       "Balance at the start of the month",
+	"payslip.earned":        "Earned",
+	"payslip.closing":       "Balance at the end of the month",
+	"payslip.generated":     "Generated %s",
+	"payslip.shift_deleted": "shift deleted (%s)",
+
+	// service errors
+	"error.shift_not_found":        "shift not found",
+	"error.payout_not_found":       "payout not found",
+	"error.payout_exceeds_balance": "the payout is larger than the unpaid balance",
+	"error.employee_not_found":     "employee not found",
+	"error.invalid_time_zone":      "unknown time zone",
+	"error.not_manager":            "only a manager can do this",
+	"error.currency_mismatch":      "there are no unpaid shifts in this currency",
+	"error.shift_paid":             "the shift is already paid, reverse the payout first",
+	"error.shift_reviewed":         "the shift has already been reviewed",
+	"error.payout_reviewed":        "the payout has already been confirmed or disputed",
+	"error.not_payout_counterpart": "only the other side can confirm the payout",
+	"error.payout_confirmed":       "the payout has been confirmed by both sides and cannot be reversed",
+	"error.shift_approved":         "the shift has been approved by a manager, only a manager can change it",
+	"error.reset_locked":           "there are approved shifts or confirmed payouts, only a manager can delete them",
+	"error.import_empty":           "the file has no shift rows",
+	"error.import_too_big":         "too many rows, at most %d",
+	"error.import_columns":         "no date and amount columns found",
+	"error.nothing_to_undo":        "nothing to undo",
+	"error.undo_expired":           "the time to undo has run out",
+	"error.undo_not_latest":        "only the latest action can be undone",
+	"error.unknown_format":         "unknown export format",
+	"error.unknown_currency":       "unknown currency",
+	"error.invalid_amount":         "invalid amount",
+	"error.unknown_lang":           "unknown language",
+
+	// import row errors
+	"import.err_date":     "invalid date %q",
+	"import.err_future":   "the date is in the future",
+	"import.err_amount":   "invalid amount %q",
+	"import.err_currency": "unknown currency %q",
+	"import.err_paid":     "unclear paid flag %q",
+
+	// system payout notes
+	"payout.note_import": "import",
+	"payout.note_legacy": "marked paid before the payout ledger",
+
+	// export files; /import reads the CSV header
+	"export.csv_header":         "record,date,amount,paid,payout_ref,note,status,currency",
+	"export.sheet_shifts":       "Shifts",
+	"export.sheet_payouts":      "Payouts",
+	"export.sheet_summary":      "Summary",
+	"export.col_date":           "Date",
+	"export.col_amount":         "Amount",
+	"export.col_paid":           "Paid",
+	"export.col_remaining":      "Remaining",
+	"export.col_payouts":        "Payouts",
+	"export.col_time":           "Time",
+	"export.col_status":         "Status",
+	"export.col_currency":       "Currency",
+	"export.col_no":             "No.",
+	"export.col_note":           "Note",
+	"export.col_month":          "Month",
+	"export.col_shifts":         "Shifts",
+	"export.col_earned":         "Earned",
+	"export.col_pending":        "Pending approval",
+	"export.col_outstanding":    "Outstanding",
+	"export.shift_pending":      "pending approval",
+	"export.shift_approved":     "approved",
+	"export.shift_rejected":     "rejected",
+	"export.payout_unconfirmed": "awaiting confirmation",
+	"export.payout_confirmed":   "confirmed",
+	"export.payout_disputed":    "disputed",
+}
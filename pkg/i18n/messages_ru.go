@@ -0,0 +1,396 @@
+package i18n
+
+// Сообщения с формами множественного числа записываются через "|":
+// одна, несколько (2–4), много.
+var ru = map[string]string{
+	// меню и общие кнопки
+	"menu.add_shift":     "➕ Добавить смену",
+	"menu.salary":        "💰 Зарплата",
+	"menu.payout":        "💸 Выплата",
+	"menu.choose":        "Выберите действие:",
+	"btn.cancel":         "❌ Отмена",
+	"btn.today":          "📅 Сегодня",
+	"btn.other_date":     "📆 Другая дата",
+	"btn.other_month":    "📊 Другой месяц",
+	"btn.range":          "🗓️ Диапазон дат",
+	"btn.payout_history": "🧾 История выплат",
+	"btn.payslip":        "📄 Расчётный листок",
+	"btn.payout_all":     "✅ Выплатить всё",
+	"btn.payout_all_in":  "✅ Всё в %s: %s",
+	"btn.at_rate":        "✅ По ставке %s",
+	"btn.reset_yes":      "✅ Да, удалить",
+	"btn.my_data":        "🙋 Мои данные",
+	"cancelled":          "Действие отменено.",
+	"cancelled.menu":     "Действие отменено. Выберите команду из меню.",
+
+	// ошибки
+	"err.generic":   "Ошибка: %s",
+	"err.data":      "Ошибка при получении данных: %s",
+	"err.salary":    "Ошибка при расчёте зарплаты: %s",
+	"err.shifts":    "Ошибка при получении смен: %s",
+	"err.payouts":   "Ошибка при получении выплат: %s",
+	"err.employees": "Ошибка при получении сотрудников: %s",
+	"err.date":      "Ошибка даты, начните заново.",
+	"err.save":      "Ошибка при сохранении: %s",
+
+	// смены
+	"shift.is_today":        "Это сегодняшняя смена?",
+	"shift.ask_amount":      "Введите сумму для смены %s:",
+	"shift.ask_amount_rate": "Введите сумму для смены %s (ставка: %s):",
+	"shift.amount_invalid":  "Некорректная сумма. Попробуйте ещё раз.",
+	"shift.amount_min":      "Сумма должна быть не менее 1. Введите сумму ещё раз.",
+	"shift.add_failed":      "Ошибка при добавлении смены: %s",
+	"shift.added":           "Смена добавлена!",
+	"shifts":                "%d смена|%d смены|%d смен",
+
+	// зарплата
+	"salary.this_month":  "Зарплата за этот месяц: %s",
+	"salary.month":       "Зарплата за %s: %s",
+	"salary.unpaid":      "Невыплачено всего: %s",
+	"salary.pending":     "⏳ Ждёт подтверждения: %s",
+	"salary.unconfirmed": "⏳ Выплаты без подтверждения: %s",
+	"salary.disputed":    "❗ Оспоренные выплаты: %s",
+	"range.pick_start":   "Выберите начальную дату диапазона",
+	"range.pick_end":     "Начало: %s\nТеперь выберите конечную дату",
+	"range.total":        "Заработано за период %s - %s: %s",
+	"month.pick":         "Выберите месяц: %d",
+
+	// выплаты
+	"payout.ask":              "Сколько выплатить? Введите сумму, выберите 'Выплатить всё' или напишите 'отмена' для выхода.",
+	"payout.amount_min":       "Сумма выплаты должна быть не менее 1. Введите сумму ещё раз.",
+	"payout.unknown_currency": "Неизвестная валюта. Доступны: %s. Введите сумму ещё раз.",
+	"payout.no_currency":      "В %s невыплаченных смен нет. Доступно к выплате: %s. Укажите валюту, например: 100 %s",
+	"payout.too_much":         "Нельзя выплатить больше, чем заработано. Доступно к выплате: %s",
+	"payout.failed":           "Ошибка при выплате: %s",
+	"payout.all_failed":       "Ошибка при полной выплате: %s",
+	"payout.done":             "Выплата на сумму %s проведена!",
+	"payout.all_done":         "Выплачено всё!",
+	"payouts.none":            "Выплат пока не было.",
+	"payouts.recent":          "Последние выплаты:",
+
+	// уведомления сотруднику о действиях менеджера
+	"notify.by_manager":  "Менеджер %s: %s",
+	"notify.shift_added": "добавлена смена %s на %s",
+	"notify.payout":      "проведена выплата на %s",
+
+	// сотрудники
+	"employees.none":       "Сотрудников пока нет.",
+	"employees.header":     "Сотрудники:",
+	"employees.line":       "• %s — невыплачено %s, %s",
+	"employees.no_shifts":  "смен нет",
+	"employees.last_shift": "последняя смена %s",
+	"employees.hint":       "Выберите сотрудника, чтобы добавлять ему смены, смотреть зарплату и проводить выплаты.",
+	"employees.selected":   "Сейчас выбран: %s",
+
+	// /resetme
+	"reset.ask":    "Удалить все ваши смены и выплаты? Это действие необратимо.",
+	"reset.hint":   "Чтобы подтвердить, нажмите кнопку или выполните: /resetme confirm",
+	"reset.done":   "Ваши данные удалены.",
+	"reset.failed": "Ошибка при сбросе данных: %s",
+
+	// /import
+	"import.help": "Пришлите CSV-файл со сменами документом.\n" +
+		"Колонки: дата, сумма и (необязательно) выплачено — да/нет. " +
+		"Можно с заголовком date,amount,paid или дата;сумма;выплачено.\n" +
+		"Даты: 2024-03-01 или 01.03.2024. Файл из /export тоже подходит.",
+	"import.only_csv":        "Я принимаю только CSV-файлы со сменами. Подробнее — /import",
+	"import.too_big":         "Файл слишком большой, максимум 1 МБ.",
+	"import.download_failed": "Не удалось скачать файл: %s",
+	"import.parse_failed":    "Не удалось разобрать файл: %s",
+	"import.header":          "Импорт смен:",
+	"import.valid":           "• к добавлению: %d",
+	"import.duplicates":      "• дубликаты (пропущены): %d",
+	"import.errors":          "• ошибки (пропущены): %d",
+	"import.line_error":      "Строка %d: %s",
+	"import.line_duplicate":  "Строка %d: уже есть смена %s на %s",
+	"import.nothing":         "Добавлять нечего.",
+	"import.confirm":         "✅ Импортировать %s",
+	"import.stale":           "Импорт устарел, пришлите файл ещё раз.",
+	"import.failed":          "Ошибка импорта: %s",
+	"import.rolled_back":     "Ошибка импорта, ничего не добавлено: %s",
+	"import.done":            "Импортировано: %s",
+
+	// /lang
+	"lang.current":  "Язык: %s.\nСменить: /lang en, /lang ru или /lang auto — по языку Telegram.",
+	"lang.auto":     "Язык: %s (по языку Telegram).\nСменить: /lang en или /lang ru.",
+	"lang.set":      "Готово, язык: %s.",
+	"lang.set_auto": "Готово: язык по настройкам Telegram — %s.",
+	"lang.unknown":  "Неизвестный язык. Доступны: %s, auto.",
+
+	// часовой пояс
+	"tz.ask": "В каком вы часовом поясе? От него зависит, какой день считать «сегодня», и время напоминаний.\n" +
+		"Отправьте местоположение кнопкой ниже или напишите смещение от UTC (+3) или название пояса (Europe/Moscow).",
+	"tz.btn_location": "📍 Отправить местоположение",
+	"tz.btn_skip":     "Пропустить",
+	"tz.current":      "Часовой пояс: %s, сейчас %s.",
+	"tz.set":          "Готово, часовой пояс: %s, сейчас %s.",
+	"tz.skipped":      "Хорошо, пока время считается по серверу. Задать пояс: /timezone",
+	"tz.invalid":      "Не понял пояс. Напишите смещение от UTC в часах, например +3 или -5, или название вроде Europe/Moscow.",
+
+	// календарь
+	"cal.pick":      "Выберите дату: %s %d",
+	"cal.bad_date":  "Ошибка даты",
+	"cal.bad_month": "Ошибка месяца",
+	"cal.stale":     "Этот календарь устарел, откройте его заново.",
+
+	// общие кнопки и ошибки
+	"btn.back":      "⬅️ Назад",
+	"err.rate":      "Ошибка при получении ставки: %s",
+	"err.rate_save": "Ошибка при сохранении ставки: %s",
+	"err.history":   "Ошибка при получении истории: %s",
+	"step.done":     "Этот шаг уже завершён. Начните заново: «%s».",
+
+	// /shifts
+	"shifts.title":            "Смены за %s:",
+	"shifts.none":             "За %s смен нет.",
+	"shifts.card":             "Смена %s\nСумма: %s",
+	"shifts.card_hourly":      "Время: %s–%s, перерыв %d мин, %s × %s",
+	"shifts.card_pending":     "⏳ Ждёт подтверждения менеджера",
+	"shifts.card_rejected":    "❌ Отклонена менеджером, в зарплату не входит",
+	"shifts.card_paid":        "Оплачено: %s\n\nИзменить оплаченную смену нельзя — сначала отмените выплату.",
+	"shifts.btn_amount":       "✏️ Сумма",
+	"shifts.btn_date":         "📅 Дата",
+	"shifts.btn_delete":       "🗑 Удалить",
+	"shifts.btn_delete_yes":   "🗑 Да, удалить",
+	"shifts.btn_reverse":      "↩️ Отменить выплату №%d (%s)",
+	"shifts.btn_dispute":      "❗ Оспорить выплату №%d (%s)",
+	"shifts.btn_to_list":      "⬅️ К списку",
+	"shifts.edit_amount":      "Введите новую сумму для смены %s (сейчас %s):",
+	"shifts.delete_ask":       "Удалить смену %s на %s?",
+	"shifts.deleted":          "Смена %s удалена.",
+	"shifts.restart":          "Ошибка, начните заново: /shifts",
+	"shifts.not_found":        "Смена не найдена.",
+	"shifts.paid":             "Смена уже оплачена. Сначала отмените выплату в карточке смены.",
+	"shifts.approved_locked":  "Смена подтверждена менеджером. Изменить или удалить её может только менеджер.",
+	"shifts.card_approved":    "\nСмена подтверждена менеджером — изменить её может только менеджер.",
+	"payout.not_found":        "Выплата не найдена.",
+	"payout.btn_reverse_yes":  "↩️ Да, отменить выплату",
+	"payout.reverse_ask":      "Отменить выплату №%d от %s на %s? Все смены, которые она покрывала, снова станут невыплаченными.",
+	"payout.reversed":         "Выплата №%d на %s отменена. Теперь смены можно изменить через /shifts.",
+	"payout.btn_dispute_yes":  "❗ Да, я её не получал",
+	"payout.dispute_ask":      "Выплату №%d от %s на %s записал менеджер, удалить её нельзя. Оспорить её — вы её не получали?",
+	"payout.confirmed_locked": "Выплата подтверждена обеими сторонами, отменить её нельзя. Если это ошибка, обратитесь к менеджеру.",
+
+	// ставки
+	"rate.none":              "Ставка не задана. Задать: /rate 2500 или /rate 2500 01.09.2025",
+	"rate.current_shift":     "Текущая ставка: %s за смену",
+	"rate.current_hour":      "Текущая ставка: %s в час",
+	"rate.role_manager_only": "Ставки для ролей задаёт только менеджер.",
+	"rate.role_usage":        "Пример: /rate role employee 2500 01.09.2025",
+	"rate.unknown_role":      "Неизвестная роль: %s",
+	"rate.role_invalid":      "Некорректная ставка или дата. Пример: /rate role employee 2500 01.09.2025",
+	"rate.invalid":           "Некорректная ставка или дата. Пример: /rate 2500 01.09.2025",
+	"rate.role_set":          "Ставка для роли %s: %s с %s",
+	"rate.set":               "Ставка %s действует с %s",
+	"rate.not_set_enter":     "Ставка на эту дату не задана, введите сумму.",
+	"shift.added_at_rate":    "Смена добавлена! По ставке %s",
+
+	// почасовая оплата
+	"hourly.current":        "Сейчас почасовая оплата, ставка %s в час.\nОтключить: /hourly off",
+	"hourly.per_shift":      "Сейчас оплата за смену. Включить почасовую: /hourly <ставка в час>, например /hourly 350",
+	"hourly.disabled":       "Готово: оплата за смену.",
+	"hourly.invalid":        "Некорректная ставка. Пример: /hourly 350",
+	"hourly.enabled":        "Готово: почасовая оплата, %s в час. При добавлении смены бот спросит время начала и конца.",
+	"hourly.ask_times":      "Введите время смены %s в формате 09:00-18:00.\nЕсли был перерыв, добавьте минуты через пробел: 09:00-18:00 60",
+	"hourly.times_invalid":  "Не понял время. Пример: 09:00-18:00 или 22:00-06:00 30",
+	"hourly.no_rate":        "Ставка в час не задана: /hourly <ставка> или /rate <ставка>.",
+	"hourly.break_too_long": "Перерыв не может быть длиннее смены. Введите время ещё раз.",
+	"hourly.added":          "Смена добавлена! %s × %s = %s",
+	"worked.hours":          "%d ч",
+	"worked.hours_minutes":  "%d ч %d мин",
+
+	// вечерний вопрос
+	"remind.off":        "Вечерний вопрос о смене выключен. Включить: /remind 20:30",
+	"remind.current":    "Спрашиваю о смене каждый день в %s. Выключить: /remind off",
+	"remind.invalid":    "Некорректное время. Пример: /remind 20:30",
+	"remind.disabled":   "Вечерний вопрос о смене выключен.",
+	"remind.set":        "Буду спрашивать о смене каждый день в %s.",
+	"evening.ask":       "Вы сегодня работали?",
+	"evening.btn_rate":  "Да, по ставке",
+	"evening.btn_other": "Да, другая сумма",
+	"evening.btn_no":    "Нет",
+	"evening.no_rate":   "Ставка не задана (/rate).",
+	"evening.added":     "Смена за %s добавлена по ставке %s",
+	"evening.shift_for": "Смена за %s",
+	"evening.rest":      "Хорошо, отдыхайте!",
+
+	// /undo
+	"undo.btn":         "↩️ Отменить",
+	"undo.payout_done": "Выплата отменена.",
+	"undo.shift_done":  "Добавление смены отменено.",
+	"undo.nothing":     "Нечего отменять.",
+	"undo.expired":     "Время на отмену истекло. Исправить смену можно через /shifts.",
+	"undo.not_latest":  "Отменить можно только последнее действие: /undo",
+	"undo.shift_paid":  "Смена уже оплачена. Сначала отмените выплату.",
+	"undo.failed":      "Ошибка при отмене: %s",
+
+	// /currency
+	"currency.current": "Валюта оплаты: %s.\nСменить: /currency <код>, доступны: %s",
+	"currency.unknown": "Неизвестная валюта. Доступны: %s",
+	"currency.set":     "Готово: новые смены записываются в %s. Прежние смены и выплаты остаются в своей валюте.",
+
+	// /history
+	"history.none":           "Изменений пока нет.",
+	"history.header":         "Последние изменения:",
+	"audit.shift_created":    "добавлена смена %s на %s",
+	"audit.shift_updated":    "изменена смена %s: %s",
+	"audit.date_changed":     "дата %s → %s",
+	"audit.amount_changed":   "сумма %s → %s",
+	"audit.approved":         "подтверждена",
+	"audit.rejected":         "отклонена",
+	"audit.pending":          "ждёт подтверждения",
+	"audit.shift_deleted":    "удалена смена %s на %s",
+	"audit.shifts_deleted":   "удалены все смены",
+	"audit.payout_created":   "выплата №%d на %s",
+	"audit.payout_confirmed": "выплата №%d на %s подтверждена",
+	"audit.payout_disputed":  "выплата №%d на %s оспорена",
+	"audit.payout_deleted":   "отменена выплата №%d на %s",
+	"audit.payouts_deleted":  "удалены все выплаты",
+	"audit.employee_created": "регистрация сотрудника",
+	"audit.employee_updated": "изменён профиль сотрудника",
+	"audit.rate":             "ставка %s с %s",
+
+	// работа с данными сотрудника
+	"act.failed":  "Не удалось выбрать сотрудника: %s",
+	"act.self":    "Вы снова работаете со своими данными.",
+	"act.other":   "Теперь «%s», «%s» и «%s» работают с данными %s. Вернуться к себе: /employees → «%s».",
+	"auth.denied": "⛔ Нет доступа.",
+
+	// подтверждение смен
+	"approval.request":         "Смена на подтверждение\n👤 %s\nДата: %s\nСумма: %s",
+	"approval.btn_approve":     "✅ Подтвердить",
+	"approval.btn_reject":      "❌ Отклонить",
+	"approval.btn_approve_all": "✅ Подтвердить все",
+	"approval.pending":         "⏳ Смена ждёт подтверждения менеджера.",
+	"approval.import_request":  "👤 %s импортировал %s\nЖдут подтверждения на сумму %s",
+	"approval.import_pending":  "⏳ Смены ждут подтверждения менеджера.",
+	"approval.reviewed":        "Смена уже рассмотрена или удалена.",
+	"approval.approved":        "подтверждена ✅",
+	"approval.rejected":        "отклонена ❌",
+	"approval.shift":           "Смена %s на %s %s",
+	"approval.approved_n":      "Подтверждено смен: %d",
+	"notify.shift_reviewed":    "смена %s на %s %s",
+	"notify.approved_n":        "подтверждено смен: %d",
+
+	// подтверждение выплат
+	"payout.awaits_manager":   "⏳ Выплата ждёт подтверждения менеджера.",
+	"payout.awaits_employee":  "⏳ Выплата ждёт подтверждения сотрудника.",
+	"payout.btn_received":     "✅ Получено",
+	"payout.btn_not_received": "❌ Не получено",
+	"payout.btn_paid":         "✅ Выплачено",
+	"payout.btn_not_paid":     "❌ Не выплачено",
+	"payout.ask_employee":     "Менеджер %s записал выплату №%d от %s на %s. Вы её получили?",
+	"payout.ask_manager":      "👤 %s записал выплату №%d от %s на %s. Подтвердите, что она была.",
+	"payout.reminder":         "⚠️ Напоминание. ",
+	"payout.not_confirmed":    "⚠️ %s не подтвердил выплату №%d от %s на %s.",
+	"payout.already_reviewed": "Выплата уже подтверждена, оспорена или отменена.",
+	"payout.confirmed":        "Выплата №%d от %s на %s подтверждена ✅",
+	"payout.disputed":         "Выплата №%d от %s на %s оспорена ❌\nСмены, которые она покрывала, снова считаются невыплаченными.",
+
+	// /export
+	"export.title":      "Выгрузка. %s",
+	"export.pick_start": "Выберите начальную дату выгрузки",
+	"export.format":     "Выгрузка за %s – %s. Выберите формат:",
+	"export.preparing":  "Готовлю выгрузку за %s – %s…",
+	"export.failed":     "Ошибка при подготовке выгрузки: %s",
+
+	// расчётный листок
+	"payslip.title":  "Расчётный листок. %s",
+	"payslip.for":    "Расчётный листок за %s:",
+	"payslip.failed": "Ошибка при подготовке расчётного листка: %s",
+
+	// напоминание о дне выплаты
+	"reminder.today":      "Сегодня, %s, день выплаты.",
+	"reminder.missed":     "%s был день выплаты.",
+	"reminder.unpaid":     "Невыплачено: %s",
+	"reminder.btn_payout": "💸 Записать выплату",
+
+	// HTML расчётного листка
+	"payslip.doc_title":     "Расчётный листок — %s",
+	"payslip.heading":       "Расчётный листок за %s",
+	"payslip.shifts":        "Смены",
+	"payslip.col_date":      "Дата",
+	"payslip.col_time":      "Время",
+	"payslip.col_amount":    "Сумма",
+	"payslip.col_paid":      "Выплачено",
+	"payslip.break":         ", перерыв %d мин",
+	"payslip.no_shifts":     "Смен в этом месяце нет.",
+	"payslip.adjustments":   "Корректировки",
+	"payslip.col_shift":     "Смена",
+	"payslip.col_change":    "Изменение",
+	"payslip.col_when":      "Когда",
+	"payslip.payouts":       "Выплаты",
+	"payslip.col_no":        "№",
+	"payslip.col_note":      "Комментарий",
+	"payslip.no_payouts":    "Выплат в этом месяце не было.",
+	"payslip.totals":        "Итого",
+	"payslip.opening":       "Остаток на начало месяца",
+	"payslip.earned":        "Начислено",
+	"payslip.closing":       "Остаток на конец месяца",
+	"payslip.generated":     "Сформирован %s",
+	"payslip.shift_deleted": "смена удалена (%s)",
+
+	// ошибки сервисов
+	"error.shift_not_found":        "смена не найдена",
+	"error.payout_not_found":       "выплата не найдена",
+	"error.payout_exceeds_balance": "сумма выплаты больше невыплаченного остатка",
+	"error.employee_not_found":     "сотрудник не найден",
+	"error.invalid_time_zone":      "неизвестный часовой пояс",
+	"error.not_manager":            "действие доступно только менеджеру",
+	"error.currency_mismatch":      "в этой валюте нет невыплаченных смен",
+	"error.shift_paid":             "смена уже оплачена, сначала отмените выплату",
+	"error.shift_reviewed":         "смена уже рассмотрена",
+	"error.payout_reviewed":        "выплата уже подтверждена или оспорена",
+	"error.not_payout_counterpart": "подтвердить выплату может только другая сторона",
+	"error.payout_confirmed":       "выплата подтверждена обеими сторонами, отменить её нельзя",
+	"error.shift_approved":         "смена подтверждена менеджером, изменить её может только менеджер",
+	"error.reset_locked":           "есть подтверждённые смены или выплаты, удалить их может только менеджер",
+	"error.import_empty":           "в файле нет строк со сменами",
+	"error.import_too_big":         "слишком много строк, максимум %d",
+	"error.import_columns":         "не найдены колонки с датой и суммой",
+	"error.nothing_to_undo":        "нечего отменять",
+	"error.undo_expired":           "время на отмену истекло",
+	"error.undo_not_latest":        "отменить можно только последнее действие",
+	"error.unknown_format":         "неизвестный формат выгрузки",
+	"error.unknown_currency":       "неизвестная валюта",
+	"error.invalid_amount":         "некорректная сумма",
+	"error.unknown_lang":           "неизвестный язык",
+
+	// ошибки в строках импорта
+	"import.err_date":     "некорректная дата %q",
+	"import.err_future":   "дата в будущем",
+	"import.err_amount":   "некорректная сумма %q",
+	"import.err_currency": "неизвестная валюта %q",
+	"import.err_paid":     "непонятный признак выплаты %q",
+
+	// служебные комментарии выплат
+	"payout.note_import": "импорт",
+	"payout.note_legacy": "отмечено выплаченным до журнала выплат",
+
+	// файлы выгрузки; заголовок CSV читает /import
+	"export.csv_header":         "запись,дата,сумма,выплачено,выплаты,комментарий,статус,валюта",
+	"export.sheet_shifts":       "Смены",
+	"export.sheet_payouts":      "Выплаты",
+	"export.sheet_summary":      "Сводка",
+	"export.col_date":           "Дата",
+	"export.col_amount":         "Сумма",
+	"export.col_paid":           "Выплачено",
+	"export.col_remaining":      "Остаток",
+	"export.col_payouts":        "Выплаты",
+	"export.col_time":           "Время",
+	"export.col_status":         "Статус",
+	"export.col_currency":       "Валюта",
+	"export.col_no":             "№",
+	"export.col_note":           "Комментарий",
+	"export.col_month":          "Месяц",
+	"export.col_shifts":         "Смен",
+	"export.col_earned":         "Начислено",
+	"export.col_pending":        "Ждёт подтверждения",
+	"export.col_outstanding":    "Остаток по сменам",
+	"export.shift_pending":      "ждёт подтверждения",
+	"export.shift_approved":     "подтверждена",
+	"export.shift_rejected":     "отклонена",
+	"export.payout_unconfirmed": "ждёт подтверждения",
+	"export.payout_confirmed":   "подтверждена",
+	"export.payout_disputed":    "оспорена",
+}
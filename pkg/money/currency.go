@@ -0,0 +1,178 @@
+package money
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Currency — код валюты ISO 4217.
+type Currency string
+
+const (
+	RUB Currency = "RUB"
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+	KZT Currency = "KZT"
+)
+
+// DefaultCurrency — валюта сумм, записанных до появления валют.
+const DefaultCurrency = RUB
+
+var ErrUnknownCurrency = errors.New("неизвестная валюта")
+
+type currencyFormat struct {
+	symbol string
+	// prefix — символ пишется перед суммой: $15.00, но 15.00 ₽.
+	prefix bool
+}
+
+var currencies = map[Currency]currencyFormat{
+	RUB: {symbol: "₽"},
+	USD: {symbol: "$", prefix: true},
+	EUR: {symbol: "€", prefix: true},
+	KZT: {symbol: "₸"},
+}
+
+// Currencies возвращает поддерживаемые валюты по алфавиту.
+func Currencies() []Currency {
+	list := make([]Currency, 0, len(currencies))
+	for c := range currencies {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+// ParseCurrency разбирает код ("usd") или символ ("$") валюты.
+func ParseCurrency(s string) (Currency, error) {
+	s = strings.TrimSpace(s)
+	c := Currency(strings.ToUpper(s))
+	if _, ok := currencies[c]; ok {
+		return c, nil
+	}
+	for c, f := range currencies {
+		if f.symbol == s {
+			return c, nil
+		}
+	}
+	switch strings.ToLower(s) {
+	case "р", "руб", "рублей":
+		return RUB, nil
+	case "тг", "тенге":
+		return KZT, nil
+	}
+	return "", ErrUnknownCurrency
+}
+
+// ParseWithCurrency разбирает сумму с необязательной валютой до или после
+// числа: "1500", "1500 руб", "$20", "20 usd". Если валюта не указана,
+// возвращается пустая Currency.
+func ParseWithCurrency(s string) (Amount, Currency, error) {
+	isNum := func(r rune) bool {
+		return r >= '0' && r <= '9' || strings.ContainsRune("+-.,  \u00a0", r)
+	}
+	start := strings.IndexFunc(s, isNum)
+	if start < 0 {
+		return 0, "", ErrInvalidAmount
+	}
+	end := strings.LastIndexFunc(s, isNum)
+	_, size := utf8.DecodeRuneInString(s[end:])
+	end += size
+	a, err := Parse(s[start:end])
+	if err != nil {
+		return 0, "", err
+	}
+	before, after := strings.TrimSpace(s[:start]), strings.TrimSpace(s[end:])
+	if before == "" && after == "" {
+		return a, "", nil
+	}
+	if before != "" && after != "" {
+		return 0, "", ErrInvalidAmount
+	}
+	c, err := ParseCurrency(before + after)
+	if err != nil {
+		return 0, "", err
+	}
+	return a, c, nil
+}
+
+// OrDefault подставляет DefaultCurrency вместо пустой валюты.
+func (c Currency) OrDefault() Currency {
+	if c == "" {
+		return DefaultCurrency
+	}
+	return c
+}
+
+func (c Currency) Symbol() string {
+	if f, ok := currencies[c.OrDefault()]; ok {
+		return f.symbol
+	}
+	return string(c)
+}
+
+// Format пишет сумму с символом валюты: "1500.00 ₽", "$20.00".
+func (c Currency) Format(a Amount) string {
+	return c.FormatWith(a, Amount.String)
+}
+
+// FormatWith — то же, что Format, но число пишется функцией number,
+// например с разделителями языка пользователя.
+func (c Currency) FormatWith(a Amount, number func(Amount) string) string {
+	f, ok := currencies[c.OrDefault()]
+	if !ok {
+		return number(a) + " " + string(c)
+	}
+	if f.prefix {
+		if a < 0 {
+			return "-" + f.symbol + number(-a)
+		}
+		return f.symbol + number(a)
+	}
+	return number(a) + " " + f.symbol
+}
+
+// Totals — суммы по валютам. Суммы в разных валютах не складываются,
+// поэтому итоги по сменам и выплатам считаются для каждой валюты отдельно.
+type Totals map[Currency]Amount
+
+func (t Totals) Add(c Currency, a Amount) {
+	c = c.OrDefault()
+	t[c] += a
+	if t[c] == 0 {
+		delete(t, c)
+	}
+}
+
+// Currencies возвращает валюты с ненулевой суммой по алфавиту.
+func (t Totals) Currencies() []Currency {
+	list := make([]Currency, 0, len(t))
+	for c := range t {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+func (t Totals) IsZero() bool {
+	return len(t) == 0
+}
+
+// String перечисляет суммы через запятую: "1500.00 ₽, $20.00".
+// Пустые итоги пишутся нулём в валюте по умолчанию.
+func (t Totals) String() string {
+	return t.FormatWith(Amount.String)
+}
+
+func (t Totals) FormatWith(number func(Amount) string) string {
+	if t.IsZero() {
+		return DefaultCurrency.FormatWith(0, number)
+	}
+	parts := make([]string, 0, len(t))
+	for _, c := range t.Currencies() {
+		parts = append(parts, c.FormatWith(t[c], number))
+	}
+	return strings.Join(parts, ", ")
+}
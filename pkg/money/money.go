@@ -0,0 +1,142 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Amount — денежная сумма в минимальных единицах (копейках).
+// Хранится как целое, поэтому суммы по сотням смен не накапливают ошибку.
+type Amount int64
+
+var ErrInvalidAmount = errors.New("некорректная сумма")
+
+func FromMinor(v int64) Amount {
+	return Amount(v)
+}
+
+// FromFloat округляет значение в рублях до копеек.
+func FromFloat(v float64) Amount {
+	return Amount(math.Round(v * 100))
+}
+
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Float нужен только для мест, где требуется приблизительное значение
+// (например, числовые ячейки в выгрузках).
+func (a Amount) Float() float64 {
+	return float64(a) / 100
+}
+
+// Parse разбирает сумму вида "1500", "1500.5", "1 500,50".
+// Больше двух знаков после разделителя не допускается.
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	s = strings.ReplaceAll(s, " ", "")
+	s = strings.ReplaceAll(s, "\u00a0", "")
+	s = strings.Replace(s, ",", ".", 1)
+	if s == "" {
+		return 0, ErrInvalidAmount
+	}
+	neg := false
+	if s[0] == '-' || s[0] == '+' {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	intPart, fracPart, hasFrac := strings.Cut(s, ".")
+	if intPart == "" && !hasFrac || len(fracPart) > 2 || hasFrac && fracPart == "" {
+		return 0, ErrInvalidAmount
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	units, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil || units < 0 {
+		return 0, ErrInvalidAmount
+	}
+	for len(fracPart) < 2 {
+		fracPart += "0"
+	}
+	cents, err := strconv.ParseInt(fracPart, 10, 64)
+	if err != nil || cents < 0 {
+		return 0, ErrInvalidAmount
+	}
+	if units > (math.MaxInt64-cents)/100 {
+		return 0, ErrInvalidAmount
+	}
+	v := units*100 + cents
+	if neg {
+		v = -v
+	}
+	return Amount(v), nil
+}
+
+// String форматирует сумму с двумя знаками после точки: "1500.00".
+func (a Amount) String() string {
+	v := int64(a)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/100, v%100)
+}
+
+// Format пишет сумму с заданными разделителями дробной части и разрядов:
+// Format(",", " ") даёт "1 500,00", Format(".", ",") — "1,500.00".
+func (a Amount) Format(decimal, group string) string {
+	v := int64(a)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	units := strconv.FormatInt(v/100, 10)
+	var b strings.Builder
+	for i, d := range units {
+		if i > 0 && (len(units)-i)%3 == 0 {
+			b.WriteString(group)
+		}
+		b.WriteRune(d)
+	}
+	return fmt.Sprintf("%s%s%s%02d", sign, b.String(), decimal, v%100)
+}
+
+func (a Amount) Value() (driver.Value, error) {
+	return int64(a), nil
+}
+
+func (a *Amount) Scan(src any) error {
+	switch v := src.(type) {
+	case int64:
+		*a = Amount(v)
+	case float64:
+		// SQLite может вернуть REAL для вычисляемых выражений
+		*a = Amount(math.Round(v))
+	case nil:
+		*a = 0
+	default:
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+	return nil
+}
+
+// MarshalJSON пишет сумму точным десятичным числом: 1500.50.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	v, err := Parse(strings.Trim(string(b), `"`))
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
@@ -0,0 +1,220 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+		err  bool
+	}{
+		{in: "1500", want: 150000},
+		{in: "1500.5", want: 150050},
+		{in: "1500.05", want: 150005},
+		{in: "1500,5", want: 150050},
+		{in: "1 500,50", want: 150050},
+		{in: "1 500", want: 150000},
+		{in: " 42 ", want: 4200},
+		{in: ".5", want: 50},
+		{in: "0", want: 0},
+		{in: "+10", want: 1000},
+		{in: "-10.25", want: -1025},
+		{in: "-0.01", want: -1},
+		{in: "92233720368547758.07", want: 9223372036854775807},
+		{in: "92233720368547758.08", err: true},
+		{in: "99999999999999999999", err: true},
+		{in: "1.005", err: true},
+		{in: "1,500.50", err: true},
+		{in: "1.", err: true},
+		{in: "", err: true},
+		{in: "-", err: true},
+		{in: "--5", err: true},
+		{in: "1.-5", err: true},
+		{in: "abc", err: true},
+		{in: "12a", err: true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if tt.err {
+			if !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("Parse(%q) = %v, %v; want ErrInvalidAmount", tt.in, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want Amount
+	}{
+		{1500, 150000},
+		{0.1 + 0.2, 30},
+		{0.125, 13},
+		{-0.125, -13},
+		{1.005, 100}, // 1.005 в float64 чуть меньше 1.005
+		{19.999, 2000},
+		{-1500.5, -150050},
+	}
+	for _, tt := range tests {
+		if got := FromFloat(tt.in); got != tt.want {
+			t.Errorf("FromFloat(%v) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in   Amount
+		want string
+	}{
+		{0, "0.00"},
+		{1, "0.01"},
+		{150050, "1500.50"},
+		{-1, "-0.01"},
+		{-150005, "-1500.05"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Amount(%d).String() = %q, want %q", int64(tt.in), got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		in             Amount
+		decimal, group string
+		want           string
+	}{
+		{0, ",", " ", "0,00"},
+		{99999, ",", " ", "999,99"},
+		{100000, ",", " ", "1 000,00"},
+		{150050, ".", ",", "1,500.50"},
+		{123456789012, ".", ",", "1,234,567,890.12"},
+		{-150050, ",", " ", "-1 500,50"},
+		{-5, ".", ",", "-0.05"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.Format(tt.decimal, tt.group); got != tt.want {
+			t.Errorf("Amount(%d).Format(%q, %q) = %q, want %q", int64(tt.in), tt.decimal, tt.group, got, tt.want)
+		}
+	}
+}
+
+func TestStringParseRoundTrip(t *testing.T) {
+	for _, a := range []Amount{0, 1, -1, 99, 100, 150050, -150050, 9223372036854775807} {
+		got, err := Parse(a.String())
+		if err != nil || got != a {
+			t.Errorf("Parse(%q) = %v, %v; want %d", a.String(), got, err, int64(a))
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		src  any
+		want Amount
+	}{
+		{int64(150050), 150050},
+		{float64(12.6), 13},
+		{float64(-12.5), -13},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		var a Amount
+		if err := a.Scan(tt.src); err != nil || a != tt.want {
+			t.Errorf("Scan(%v) = %d, %v; want %d", tt.src, a, err, tt.want)
+		}
+	}
+	var a Amount
+	if err := a.Scan("1500"); err == nil {
+		t.Error("Scan(string): want error")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	b, err := json.Marshal(struct{ A Amount }{150050})
+	if err != nil || string(b) != `{"A":1500.50}` {
+		t.Fatalf("Marshal = %s, %v", b, err)
+	}
+	var v struct{ A Amount }
+	if err := json.Unmarshal([]byte(`{"A":"-0.5"}`), &v); err != nil || v.A != -50 {
+		t.Fatalf("Unmarshal = %d, %v", v.A, err)
+	}
+}
+
+func TestParseWithCurrency(t *testing.T) {
+	tests := []struct {
+		in       string
+		amount   Amount
+		currency Currency
+		err      bool
+	}{
+		{in: "1500", amount: 150000},
+		{in: "1500 руб", amount: 150000, currency: RUB},
+		{in: "$20", amount: 2000, currency: USD},
+		{in: "20.5 usd", amount: 2050, currency: USD},
+		{in: "1 000 ₸", amount: 100000, currency: KZT},
+		{in: "€ 10", amount: 1000, currency: EUR},
+		{in: "$20 usd", err: true},
+		{in: "20 xyz", err: true},
+		{in: "usd", err: true},
+	}
+	for _, tt := range tests {
+		a, c, err := ParseWithCurrency(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseWithCurrency(%q) = %v %q; want error", tt.in, a, c)
+			}
+			continue
+		}
+		if err != nil || a != tt.amount || c != tt.currency {
+			t.Errorf("ParseWithCurrency(%q) = %v %q, %v; want %v %q", tt.in, a, c, err, tt.amount, tt.currency)
+		}
+	}
+}
+
+func TestCurrencyFormat(t *testing.T) {
+	tests := []struct {
+		c    Currency
+		a    Amount
+		want string
+	}{
+		{"", 150000, "1500.00 ₽"},
+		{RUB, -150000, "-1500.00 ₽"},
+		{USD, 2000, "$20.00"},
+		{USD, -2000, "-$20.00"},
+		{"GBP", 100, "1.00 GBP"},
+	}
+	for _, tt := range tests {
+		if got := tt.c.Format(tt.a); got != tt.want {
+			t.Errorf("%q.Format(%d) = %q, want %q", tt.c, int64(tt.a), got, tt.want)
+		}
+	}
+}
+
+func TestTotals(t *testing.T) {
+	tot := Totals{}
+	if got := tot.String(); got != "0.00 ₽" {
+		t.Errorf("empty Totals = %q", got)
+	}
+	tot.Add("", 100)
+	tot.Add(USD, 2000)
+	tot.Add(RUB, 50)
+	if got := tot.String(); got != "1.50 ₽, $20.00" {
+		t.Errorf("Totals = %q", got)
+	}
+	tot.Add(USD, -2000)
+	if _, ok := tot[USD]; ok || len(tot) != 1 {
+		t.Errorf("zero currency kept: %v", tot)
+	}
+}
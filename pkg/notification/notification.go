@@ -1,3 +1,45 @@
+// Package notification описывает расписания «в такие-то дни месяца в
+// такое-то время», например для напоминаний о дне выплаты.
 package notification
 
+import (
+	"sort"
+	"time"
+)
 
+// Schedule — дни месяца и время суток срабатывания. Если в месяце нет
+// такого дня (31 в апреле), задача срабатывает в последний день месяца.
+type Schedule struct {
+	Days []int
+	// At — время суток, смещение от полуночи.
+	At       time.Duration
+	Location *time.Location
+}
+
+// Next возвращает ближайший момент срабатывания строго после after.
+func (s Schedule) Next(after time.Time) time.Time {
+	loc := s.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	after = after.In(loc)
+	days := append([]int(nil), s.Days...)
+	sort.Ints(days)
+	for m := 0; m < 13; m++ {
+		first := time.Date(after.Year(), after.Month()+time.Month(m), 1, 0, 0, 0, 0, loc)
+		last := first.AddDate(0, 1, -1).Day()
+		for _, d := range days {
+			if d < 1 {
+				continue
+			}
+			// время по часам пояса, а не сдвиг от полуночи: в день перехода
+			// на летнее время сутки короче
+			at := time.Date(first.Year(), first.Month(), min(d, last),
+				int(s.At/time.Hour), int(s.At%time.Hour/time.Minute), 0, 0, loc)
+			if at.After(after) {
+				return at
+			}
+		}
+	}
+	return time.Time{}
+}
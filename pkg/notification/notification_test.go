@@ -0,0 +1,122 @@
+package notification
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	utc := time.UTC
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	at := func(loc *time.Location, y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, loc)
+	}
+	tests := []struct {
+		name     string
+		schedule Schedule
+		after    time.Time
+		want     time.Time
+	}{
+		{
+			name:     "ближайший день в этом месяце",
+			schedule: Schedule{Days: []int{10, 25}, At: 10 * time.Hour, Location: utc},
+			after:    at(utc, 2024, 3, 5, 12, 0),
+			want:     at(utc, 2024, 3, 10, 10, 0),
+		},
+		{
+			name:     "строго после: в момент срабатывания — следующее",
+			schedule: Schedule{Days: []int{10, 25}, At: 10 * time.Hour, Location: utc},
+			after:    at(utc, 2024, 3, 10, 10, 0),
+			want:     at(utc, 2024, 3, 25, 10, 0),
+		},
+		{
+			name:     "в тот же день, но раньше времени",
+			schedule: Schedule{Days: []int{10}, At: 10*time.Hour + 30*time.Minute, Location: utc},
+			after:    at(utc, 2024, 3, 10, 10, 29),
+			want:     at(utc, 2024, 3, 10, 10, 30),
+		},
+		{
+			name:     "дни не по порядку",
+			schedule: Schedule{Days: []int{25, 10}, At: 10 * time.Hour, Location: utc},
+			after:    at(utc, 2024, 3, 11, 0, 0),
+			want:     at(utc, 2024, 3, 25, 10, 0),
+		},
+		{
+			name:     "переход через год",
+			schedule: Schedule{Days: []int{10, 25}, At: 10 * time.Hour, Location: utc},
+			after:    at(utc, 2024, 12, 26, 0, 0),
+			want:     at(utc, 2025, 1, 10, 10, 0),
+		},
+		{
+			name:     "31-е в високосном феврале — 29-е",
+			schedule: Schedule{Days: []int{31}, At: 10 * time.Hour, Location: utc},
+			after:    at(utc, 2024, 1, 31, 12, 0),
+			want:     at(utc, 2024, 2, 29, 10, 0),
+		},
+		{
+			name:     "31-е в обычном феврале — 28-е",
+			schedule: Schedule{Days: []int{31}, At: 10 * time.Hour, Location: utc},
+			after:    at(utc, 2023, 2, 1, 0, 0),
+			want:     at(utc, 2023, 2, 28, 10, 0),
+		},
+		{
+			name:     "31-е в апреле — 30-е",
+			schedule: Schedule{Days: []int{31}, At: 10 * time.Hour, Location: utc},
+			after:    at(utc, 2024, 3, 31, 10, 0),
+			want:     at(utc, 2024, 4, 30, 10, 0),
+		},
+		{
+			name:     "30 и 31 в феврале срабатывают один раз",
+			schedule: Schedule{Days: []int{30, 31}, At: 10 * time.Hour, Location: utc},
+			after:    at(utc, 2024, 2, 29, 10, 0),
+			want:     at(utc, 2024, 3, 30, 10, 0),
+		},
+		{
+			name:     "пояс расписания, момент в UTC",
+			schedule: Schedule{Days: []int{10}, At: 10 * time.Hour, Location: tokyo},
+			after:    at(utc, 2024, 3, 9, 23, 0), // 10.03 08:00 в Токио
+			want:     at(tokyo, 2024, 3, 10, 10, 0),
+		},
+		{
+			name:     "день перехода на летнее время",
+			schedule: Schedule{Days: []int{31}, At: 10 * time.Hour, Location: berlin},
+			after:    at(berlin, 2024, 3, 30, 0, 0),
+			want:     at(berlin, 2024, 3, 31, 10, 0),
+		},
+		{
+			name:     "день перехода на зимнее время",
+			schedule: Schedule{Days: []int{27}, At: 10 * time.Hour, Location: berlin},
+			after:    at(berlin, 2024, 10, 26, 0, 0),
+			want:     at(berlin, 2024, 10, 27, 10, 0),
+		},
+		{
+			name:     "некорректные дни пропускаются",
+			schedule: Schedule{Days: []int{0, -1, 15}, At: 0, Location: utc},
+			after:    at(utc, 2024, 3, 1, 0, 0),
+			want:     at(utc, 2024, 3, 15, 0, 0),
+		},
+		{
+			name:     "пустое расписание",
+			schedule: Schedule{At: 10 * time.Hour, Location: utc},
+			after:    at(utc, 2024, 3, 1, 0, 0),
+		},
+		{
+			name:     "только некорректные дни",
+			schedule: Schedule{Days: []int{0}, At: 10 * time.Hour, Location: utc},
+			after:    at(utc, 2024, 3, 1, 0, 0),
+		},
+	}
+	for _, tt := range tests {
+		got := tt.schedule.Next(tt.after)
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: Next(%s) = %s, want %s", tt.name, tt.after, got, tt.want)
+		}
+	}
+}
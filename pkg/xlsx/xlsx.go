@@ -0,0 +1,257 @@
+// Package xlsx пишет простые книги Excel (Office Open XML) средствами
+// стандартной библиотеки: строки, числа и даты, без формул и стилей
+// сверх нескольких встроенных форматов.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type cellKind int
+
+const (
+	kindString cellKind = iota
+	kindNumber
+	kindMoney
+	kindDate
+	kindMonth
+	kindHeader
+)
+
+// Индексы стилей в styles.xml (cellXfs), в порядке cellKind.
+var kindStyle = map[cellKind]int{kindString: 0, kindNumber: 0, kindMoney: 1, kindDate: 2, kindMonth: 3, kindHeader: 4}
+
+type Cell struct {
+	kind cellKind
+	s    string
+	n    float64
+}
+
+func String(s string) Cell { return Cell{kind: kindString, s: s} }
+
+func Header(s string) Cell { return Cell{kind: kindHeader, s: s} }
+
+func Int(n int) Cell { return Cell{kind: kindNumber, n: float64(n)} }
+
+// Money — число с двумя знаками после запятой.
+func Money(n float64) Cell { return Cell{kind: kindMoney, n: n} }
+
+// Date — дата без времени в формате ДД.ММ.ГГГГ.
+func Date(t time.Time) Cell { return Cell{kind: kindDate, n: serial(t)} }
+
+// Month — дата, показанная как ММ.ГГГГ.
+func Month(t time.Time) Cell { return Cell{kind: kindMonth, n: serial(t)} }
+
+// serial переводит дату в число дней от 30.12.1899, как их хранит Excel.
+func serial(t time.Time) float64 {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return float64(d.Sub(epoch) / (24 * time.Hour))
+}
+
+type Sheet struct {
+	name   string
+	rows   [][]Cell
+	widths []float64
+}
+
+// AddRow добавляет строку; ширины колонок подстраиваются под содержимое.
+func (s *Sheet) AddRow(cells ...Cell) {
+	for i, c := range cells {
+		w := 12.0
+		if c.kind == kindString || c.kind == kindHeader {
+			w = float64(len([]rune(c.s))) + 2
+		}
+		for len(s.widths) <= i {
+			s.widths = append(s.widths, 10)
+		}
+		if w > s.widths[i] {
+			s.widths[i] = min(w, 60)
+		}
+	}
+	s.rows = append(s.rows, cells)
+}
+
+type Workbook struct {
+	sheets []*Sheet
+}
+
+func New() *Workbook { return &Workbook{} }
+
+// sheetNameReplacer заменяет символы, недопустимые в имени листа Excel.
+var sheetNameReplacer = strings.NewReplacer(":", "_", "\\", "_", "/", "_", "?", "_", "*", "_", "[", "(", "]", ")")
+
+// AddSheet добавляет лист. Имя приводится к ограничениям Excel: без
+// символов : \ / ? * [ ], без апострофов по краям, не длиннее 31 символа
+// и не совпадает с именем другого листа.
+func (w *Workbook) AddSheet(name string) *Sheet {
+	name = strings.Trim(sheetNameReplacer.Replace(name), "'")
+	if strings.TrimSpace(name) == "" {
+		name = fmt.Sprintf("Sheet%d", len(w.sheets)+1)
+	}
+	base := name
+	for n := 2; w.hasSheet(name); n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		name = truncateRunes(base, 31-len(suffix)) + suffix
+	}
+	name = truncateRunes(name, 31)
+	s := &Sheet{name: name}
+	w.sheets = append(w.sheets, s)
+	return s
+}
+
+// hasSheet сравнивает имена без учёта регистра, как Excel.
+func (w *Workbook) hasSheet(name string) bool {
+	for _, s := range w.sheets {
+		if strings.EqualFold(s.name, truncateRunes(name, 31)) {
+			return true
+		}
+	}
+	return false
+}
+
+func truncateRunes(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
+
+func (w *Workbook) Write(out io.Writer) error {
+	zw := zip.NewWriter(out)
+	files := []struct {
+		name string
+		body string
+	}{
+		{"[Content_Types].xml", w.contentTypes()},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", w.workbook()},
+		{"xl/_rels/workbook.xml.rels", w.workbookRels()},
+		{"xl/styles.xml", styles},
+	}
+	for i, s := range w.sheets {
+		files = append(files, struct {
+			name string
+			body string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), s.xml()})
+	}
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, f.body); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+const rootRels = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// Форматы: 1 — деньги, 2 — дата, 3 — месяц, 4 — жирный заголовок.
+const styles = xmlHeader + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="2"><numFmt numFmtId="164" formatCode="dd.mm.yyyy"/><numFmt numFmtId="165" formatCode="mm.yyyy"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="5">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
+
+func (w *Workbook) contentTypes() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range w.sheets {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func (w *Workbook) workbook() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, s := range w.sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(s.name), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func (w *Workbook) workbookRels() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range w.sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(w.sheets)+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+func (s *Sheet) xml() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(s.widths) > 0 {
+		b.WriteString(`<cols>`)
+		for i, w := range s.widths {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%.1f" customWidth="1"/>`, i+1, i+1, w)
+		}
+		b.WriteString(`</cols>`)
+	}
+	b.WriteString(`<sheetData>`)
+	for r, row := range s.rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			ref := column(c) + strconv.Itoa(r+1)
+			style := kindStyle[cell.kind]
+			switch cell.kind {
+			case kindString, kindHeader:
+				fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, escape(cell.s))
+			default:
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, strconv.FormatFloat(cell.n, 'f', -1, 64))
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// column возвращает буквенное имя колонки: 0 → A, 26 → AA.
+func column(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+	return name
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}